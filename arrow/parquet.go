@@ -0,0 +1,75 @@
+package arrow
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/apache/arrow-go/v18/arrow"
+	"github.com/apache/arrow-go/v18/arrow/array"
+	"github.com/apache/arrow-go/v18/arrow/memory"
+	"github.com/apache/arrow-go/v18/parquet"
+	"github.com/apache/arrow-go/v18/parquet/pqarrow"
+	"github.com/ieshan/timi"
+)
+
+// Parquet Helper Functions for timi.Time
+// Null times are written as Parquet nulls through the Arrow validity bitmap.
+// The Arrow schema is stored in the file so the unit and zone survive a
+// round trip; Parquet itself has no second unit and stores those columns
+// as milliseconds.
+
+// WriteParquet writes ts to w as a Parquet file with a single nullable
+// timestamp column named column.
+func WriteParquet(w io.Writer, column string, ts []timi.Time, unit arrow.TimeUnit, zone string) error {
+	field, err := TimestampField(column, unit, zone)
+	if err != nil {
+		return err
+	}
+	mem := memory.DefaultAllocator
+	arr, err := NewTimestampArray(mem, ts, unit, zone)
+	if err != nil {
+		return err
+	}
+	defer arr.Release()
+
+	schema := arrow.NewSchema([]arrow.Field{field}, nil)
+	rec := array.NewRecordBatch(schema, []arrow.Array{arr}, int64(len(ts)))
+	defer rec.Release()
+
+	fw, err := pqarrow.NewFileWriter(schema, w, parquet.NewWriterProperties(parquet.WithAllocator(mem)),
+		pqarrow.NewArrowWriterProperties(pqarrow.WithAllocator(mem), pqarrow.WithStoreSchema()))
+	if err != nil {
+		return err
+	}
+	if err = fw.Write(rec); err != nil {
+		fw.Close()
+		return err
+	}
+	return fw.Close()
+}
+
+// ReadParquet reads the timestamp column named column from a Parquet file.
+// Null entries become timi.NilTime.
+func ReadParquet(ctx context.Context, r parquet.ReaderAtSeeker, column string) ([]timi.Time, error) {
+	mem := memory.DefaultAllocator
+	tbl, err := pqarrow.ReadTable(ctx, r, parquet.NewReaderProperties(mem), pqarrow.ArrowReadProperties{}, mem)
+	if err != nil {
+		return nil, err
+	}
+	defer tbl.Release()
+
+	indices := tbl.Schema().FieldIndices(column)
+	if len(indices) == 0 {
+		return nil, fmt.Errorf("timi/arrow: column %q not found", column)
+	}
+	col := tbl.Column(indices[0])
+	if col.DataType().ID() != arrow.TIMESTAMP {
+		return nil, fmt.Errorf("timi/arrow: column %q is %s, not a timestamp", column, col.DataType())
+	}
+	ts := make([]timi.Time, 0, col.Len())
+	for _, chunk := range col.Data().Chunks() {
+		ts = append(ts, TimesFromArray(chunk.(*array.Timestamp))...)
+	}
+	return ts, nil
+}
@@ -0,0 +1,54 @@
+package arrow
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/apache/arrow-go/v18/arrow"
+	"github.com/ieshan/timi"
+)
+
+func TestParquetRoundTrip(t *testing.T) {
+	ts := []timi.Time{
+		timi.Date(2021, 1, 1, 12, 30, 45, 123456000, time.UTC),
+		timi.NilTime,
+		timi.Date(1970, 1, 1, 0, 0, 0, 0, time.UTC),
+		timi.NilTime,
+	}
+
+	for _, unit := range []arrow.TimeUnit{arrow.Second, arrow.Millisecond, arrow.Microsecond, arrow.Nanosecond} {
+		t.Run(unit.String(), func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := WriteParquet(&buf, "created_at", ts, unit, "UTC"); err != nil {
+				t.Fatalf("WriteParquet failed: %v", err)
+			}
+
+			back, err := ReadParquet(context.Background(), bytes.NewReader(buf.Bytes()), "created_at")
+			if err != nil {
+				t.Fatalf("ReadParquet failed: %v", err)
+			}
+			if len(back) != len(ts) {
+				t.Fatalf("Expected %d values, got %d", len(ts), len(back))
+			}
+			precision := time.Duration(unit.Multiplier())
+			for i, original := range ts {
+				if back[i].IsNull() != original.IsNull() {
+					t.Fatalf("Value %d: null mismatch (%v : %v)", i, original.IsNull(), back[i].IsNull())
+				}
+				if !original.IsNull() && !back[i].Equal(original.Truncate(precision)) {
+					t.Fatalf("Value %d: expected %v, got %v", i, original.Truncate(precision), back[i])
+				}
+			}
+		})
+	}
+
+	var buf bytes.Buffer
+	if err := WriteParquet(&buf, "created_at", ts, arrow.Millisecond, ""); err != nil {
+		t.Fatalf("WriteParquet failed: %v", err)
+	}
+	if _, err := ReadParquet(context.Background(), bytes.NewReader(buf.Bytes()), "updated_at"); err == nil {
+		t.Fatalf("Expecting error for missing column, but got nil")
+	}
+}
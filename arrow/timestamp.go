@@ -0,0 +1,108 @@
+package arrow
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/apache/arrow-go/v18/arrow"
+	"github.com/apache/arrow-go/v18/arrow/array"
+	"github.com/apache/arrow-go/v18/arrow/memory"
+	"github.com/ieshan/timi"
+)
+
+// Arrow Helper Functions for timi.Time
+// Arrow timestamps are instants since the Unix epoch. The time zone is only
+// metadata on the type, so values always round-trip as UTC timi.Time.
+// timi.Infinity and timi.NegInfinity are stored as the largest and smallest
+// timestamp, math.MaxInt64 and math.MinInt64, in every unit, and read back as
+// the sentinels.
+
+// TimestampType returns the Arrow timestamp type for the given unit and zone.
+// An empty zone produces a zone-naive timestamp.
+func TimestampType(unit arrow.TimeUnit, zone string) (*arrow.TimestampType, error) {
+	dt := &arrow.TimestampType{Unit: unit, TimeZone: zone}
+	if _, err := dt.GetZone(); err != nil {
+		return nil, err
+	}
+	return dt, nil
+}
+
+// TimestampField returns a nullable Arrow field for a timi.Time column.
+func TimestampField(name string, unit arrow.TimeUnit, zone string) (arrow.Field, error) {
+	dt, err := TimestampType(unit, zone)
+	if err != nil {
+		return arrow.Field{}, err
+	}
+	return arrow.Field{Name: name, Type: dt, Nullable: true}, nil
+}
+
+// AppendTimes appends ts to b using the unit of the builder's type.
+// Null times are appended as nulls. Precision finer than the unit is
+// truncated, and values outside of the unit's range are an error.
+func AppendTimes(b *array.TimestampBuilder, ts []timi.Time) error {
+	unit := b.Type().(*arrow.TimestampType).Unit
+	b.Reserve(len(ts))
+	for i, t := range ts {
+		if !t.Valid {
+			b.UnsafeAppendBoolToBitmap(false)
+			continue
+		}
+		if t.IsInfinite() {
+			b.UnsafeAppend(infinityTimestamp(t))
+			continue
+		}
+		v, err := arrow.TimestampFromTime(t.Time, unit)
+		if err != nil {
+			return fmt.Errorf("timi/arrow: value %d: %w", i, err)
+		}
+		b.UnsafeAppend(v)
+	}
+	return nil
+}
+
+// infinityTimestamp returns the timestamp of Infinity or NegInfinity.
+func infinityTimestamp(t timi.Time) arrow.Timestamp {
+	if t.IsInfinity() {
+		return math.MaxInt64
+	}
+	return math.MinInt64
+}
+
+// NewTimestampArray builds an Arrow timestamp array from ts. The validity
+// bitmap is taken from the Valid field of each value.
+// The caller is responsible for releasing the returned array.
+func NewTimestampArray(mem memory.Allocator, ts []timi.Time, unit arrow.TimeUnit, zone string) (*array.Timestamp, error) {
+	dt, err := TimestampType(unit, zone)
+	if err != nil {
+		return nil, err
+	}
+	b := array.NewTimestampBuilder(mem, dt)
+	defer b.Release()
+	if err = AppendTimes(b, ts); err != nil {
+		return nil, err
+	}
+	return b.NewTimestampArray(), nil
+}
+
+// TimesFromArray converts an Arrow timestamp array to timi.Time values.
+// Null entries become timi.NilTime.
+func TimesFromArray(arr *array.Timestamp) []timi.Time {
+	unit := arr.DataType().(*arrow.TimestampType).Unit
+	ts := make([]timi.Time, arr.Len())
+	for i := range ts {
+		if arr.IsNull(i) {
+			ts[i] = timi.NilTime
+			continue
+		}
+		switch arr.Value(i) {
+		case math.MaxInt64:
+			ts[i] = timi.Infinity
+			continue
+		case math.MinInt64:
+			ts[i] = timi.NegInfinity
+			continue
+		}
+		ts[i] = timi.Time{Time: arr.Value(i).ToTime(unit), Valid: true}
+	}
+	return ts
+}
@@ -0,0 +1,100 @@
+package arrow
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/apache/arrow-go/v18/arrow"
+	"github.com/apache/arrow-go/v18/arrow/memory"
+	"github.com/ieshan/timi"
+)
+
+func TestTimestampArray(t *testing.T) {
+	mem := memory.NewCheckedAllocator(memory.DefaultAllocator)
+	defer mem.AssertSize(t, 0)
+
+	ts := []timi.Time{
+		timi.Date(2021, 1, 1, 12, 30, 45, 123456789, time.UTC),
+		timi.NilTime,
+		timi.Date(1969, 12, 31, 23, 59, 59, 999999999, time.UTC),
+	}
+
+	testCases := []struct {
+		unit      arrow.TimeUnit
+		zone      string
+		precision time.Duration
+	}{
+		{arrow.Second, "", time.Second},
+		{arrow.Millisecond, "UTC", time.Millisecond},
+		{arrow.Microsecond, "America/New_York", time.Microsecond},
+		{arrow.Nanosecond, "+05:30", time.Nanosecond},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.unit.String(), func(t *testing.T) {
+			arr, err := NewTimestampArray(mem, ts, tc.unit, tc.zone)
+			if err != nil {
+				t.Fatalf("NewTimestampArray failed: %v", err)
+			}
+			defer arr.Release()
+
+			dt := arr.DataType().(*arrow.TimestampType)
+			if dt.Unit != tc.unit || dt.TimeZone != tc.zone {
+				t.Fatalf("Expected timestamp[%s, tz=%s], got %s", tc.unit, tc.zone, dt)
+			}
+			if arr.NullN() != 1 || !arr.IsNull(1) {
+				t.Fatalf("Expected only index 1 to be null, got %d nulls", arr.NullN())
+			}
+
+			back := TimesFromArray(arr)
+			if len(back) != len(ts) {
+				t.Fatalf("Expected %d values, got %d", len(ts), len(back))
+			}
+			for i, original := range ts {
+				if back[i].IsNull() != original.IsNull() {
+					t.Fatalf("Value %d: null mismatch (%v : %v)", i, original.IsNull(), back[i].IsNull())
+				}
+				if original.IsNull() {
+					continue
+				}
+				expected := original.Truncate(tc.precision)
+				if !back[i].Equal(expected) {
+					t.Fatalf("Value %d: expected %v, got %v", i, expected, back[i])
+				}
+				if back[i].Time.Location() != time.UTC {
+					t.Fatalf("Value %d: expected UTC, got %v", i, back[i].Time.Location())
+				}
+			}
+		})
+	}
+}
+
+func TestTimestampArrayInfinity(t *testing.T) {
+	ts := []timi.Time{timi.Infinity, timi.NegInfinity, timi.NilTime}
+	for _, unit := range []arrow.TimeUnit{arrow.Second, arrow.Millisecond, arrow.Microsecond, arrow.Nanosecond} {
+		arr, err := NewTimestampArray(memory.DefaultAllocator, ts, unit, "UTC")
+		if err != nil {
+			t.Fatalf("%s: NewTimestampArray failed: %v", unit, err)
+		}
+		if arr.Value(0) != math.MaxInt64 || arr.Value(1) != math.MinInt64 {
+			t.Fatalf("%s: expected the largest and smallest timestamp, got %d and %d", unit, arr.Value(0), arr.Value(1))
+		}
+		back := TimesFromArray(arr)
+		arr.Release()
+		for i, expected := range ts {
+			if back[i] != expected {
+				t.Fatalf("%s: value %d: expected %v, got %v", unit, i, expected, back[i])
+			}
+		}
+	}
+}
+
+func TestTimestampArrayErrors(t *testing.T) {
+	if _, err := NewTimestampArray(memory.DefaultAllocator, nil, arrow.Millisecond, "Not/AZone"); err == nil {
+		t.Fatalf("Expecting error for invalid time zone, but got nil")
+	}
+	farFuture := []timi.Time{timi.Date(2500, 1, 1, 0, 0, 0, 0, time.UTC)}
+	if _, err := NewTimestampArray(memory.DefaultAllocator, farFuture, arrow.Nanosecond, ""); err == nil {
+		t.Fatalf("Expecting error for value outside of the nanosecond range, but got nil")
+	}
+}
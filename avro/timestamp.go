@@ -0,0 +1,201 @@
+package avro
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"time"
+
+	"github.com/ieshan/timi"
+)
+
+// Avro Helper Functions for timi.Time
+// A timi.Time maps to the nullable union ["null", {"type": "long", "logicalType": ...}].
+// The null branch comes first, following the Schema Registry convention for
+// optional fields, so the field default can be null.
+//
+// timi.Infinity and timi.NegInfinity are stored as the largest and smallest
+// long, math.MaxInt64 and math.MinInt64, in every logical type, and read
+// back as the sentinels.
+
+// LogicalType is an Avro logical type annotating a long.
+type LogicalType string
+
+const (
+	TimestampMillis      LogicalType = "timestamp-millis"
+	TimestampMicros      LogicalType = "timestamp-micros"
+	LocalTimestampMillis LogicalType = "local-timestamp-millis"
+	LocalTimestampMicros LogicalType = "local-timestamp-micros"
+)
+
+// Rounding selects what happens when a time has a finer precision than the
+// logical type can hold.
+type Rounding int
+
+const (
+	// RoundError reports precision loss as an error.
+	RoundError Rounding = iota
+	// RoundTruncate drops the extra precision, rounding towards the past.
+	RoundTruncate
+	// RoundNearest rounds to the nearest unit, with halfway values rounded up.
+	RoundNearest
+)
+
+// ErrPrecisionLoss is returned with RoundError when a time cannot be encoded
+// without losing precision.
+var ErrPrecisionLoss = errors.New("timi/avro: precision loss")
+
+// Encoding describes how timi.Time values are stored in Avro.
+type Encoding struct {
+	LogicalType LogicalType
+	Rounding    Rounding
+	// Location is the zone whose wall clock is stored by the local-timestamp
+	// logical types. A nil Location means UTC. It is ignored for the
+	// timestamp logical types, which store instants.
+	Location *time.Location
+}
+
+func (e Encoding) unit() (time.Duration, error) {
+	switch e.LogicalType {
+	case TimestampMillis, LocalTimestampMillis:
+		return time.Millisecond, nil
+	case TimestampMicros, LocalTimestampMicros:
+		return time.Microsecond, nil
+	}
+	return 0, fmt.Errorf("timi/avro: unsupported logical type %q", e.LogicalType)
+}
+
+func (e Encoding) isLocal() bool {
+	return e.LogicalType == LocalTimestampMillis || e.LogicalType == LocalTimestampMicros
+}
+
+func (e Encoding) location() *time.Location {
+	if e.Location == nil {
+		return time.UTC
+	}
+	return e.Location
+}
+
+// Schema returns the Avro schema of the nullable union, for example
+// ["null",{"type":"long","logicalType":"timestamp-millis"}].
+func (e Encoding) Schema() string {
+	return `["null",{"type":"long","logicalType":` + strconv.Quote(string(e.LogicalType)) + `}]`
+}
+
+// SchemaField returns an Avro record field named name with the nullable
+// union type and a null default.
+func (e Encoding) SchemaField(name string) string {
+	return `{"name":` + strconv.Quote(name) + `,"type":` + e.Schema() + `,"default":null}`
+}
+
+// Long converts t to the long stored by the logical type.
+// It returns nil for a null Time, which selects the null branch of the
+// union. Libraries such as github.com/hamba/avro map *int64 to that union.
+func (e Encoding) Long(t timi.Time) (*int64, error) {
+	if !t.Valid {
+		return nil, nil
+	}
+	unit, err := e.unit()
+	if err != nil {
+		return nil, err
+	}
+	if t.IsInfinite() {
+		v := int64(math.MaxInt64)
+		if t.IsNegInfinity() {
+			v = math.MinInt64
+		}
+		return &v, nil
+	}
+	tv := t.Time
+	if e.isLocal() {
+		wall := tv.In(e.location())
+		tv = time.Date(wall.Year(), wall.Month(), wall.Day(), wall.Hour(), wall.Minute(), wall.Second(), wall.Nanosecond(), time.UTC)
+	}
+	switch e.Rounding {
+	case RoundError:
+		if !tv.Truncate(unit).Equal(tv) {
+			return nil, fmt.Errorf("%w: %v has a finer precision than %s", ErrPrecisionLoss, t, e.LogicalType)
+		}
+	case RoundTruncate:
+		tv = tv.Truncate(unit)
+	case RoundNearest:
+		tv = tv.Round(unit)
+	default:
+		return nil, fmt.Errorf("timi/avro: unsupported rounding %d", e.Rounding)
+	}
+	var v int64
+	if unit == time.Millisecond {
+		v = tv.UnixMilli()
+	} else {
+		v = tv.UnixMicro()
+	}
+	return &v, nil
+}
+
+// Time converts a long stored by the logical type back to a timi.Time.
+// A nil value is the null branch of the union and returns timi.NilTime.
+func (e Encoding) Time(v *int64) (timi.Time, error) {
+	if v == nil {
+		return timi.NilTime, nil
+	}
+	unit, err := e.unit()
+	if err != nil {
+		return timi.NilTime, err
+	}
+	switch *v {
+	case math.MaxInt64:
+		return timi.Infinity, nil
+	case math.MinInt64:
+		return timi.NegInfinity, nil
+	}
+	var tv time.Time
+	if unit == time.Millisecond {
+		tv = time.UnixMilli(*v).UTC()
+	} else {
+		tv = time.UnixMicro(*v).UTC()
+	}
+	if e.isLocal() {
+		tv = time.Date(tv.Year(), tv.Month(), tv.Day(), tv.Hour(), tv.Minute(), tv.Second(), tv.Nanosecond(), e.location())
+	}
+	return timi.Time{Time: tv.UTC(), Valid: true}, nil
+}
+
+// AppendBinary appends the Avro binary encoding of t as the nullable union:
+// the zig-zag varint branch index followed, for a valid Time, by the long.
+func (e Encoding) AppendBinary(b []byte, t timi.Time) ([]byte, error) {
+	v, err := e.Long(t)
+	if err != nil {
+		return b, err
+	}
+	if v == nil {
+		return binary.AppendVarint(b, 0), nil
+	}
+	return binary.AppendVarint(binary.AppendVarint(b, 1), *v), nil
+}
+
+// ReadBinary decodes a nullable union written by AppendBinary from the start
+// of b. It returns the time and the number of bytes read.
+func (e Encoding) ReadBinary(b []byte) (timi.Time, int, error) {
+	branch, n := binary.Varint(b)
+	if n <= 0 {
+		return timi.NilTime, 0, errors.New("timi/avro: invalid union branch")
+	}
+	switch branch {
+	case 0:
+		return timi.NilTime, n, nil
+	case 1:
+	default:
+		return timi.NilTime, 0, fmt.Errorf("timi/avro: invalid union branch %d", branch)
+	}
+	v, m := binary.Varint(b[n:])
+	if m <= 0 {
+		return timi.NilTime, 0, errors.New("timi/avro: invalid long")
+	}
+	t, err := e.Time(&v)
+	if err != nil {
+		return timi.NilTime, 0, err
+	}
+	return t, n + m, nil
+}
@@ -0,0 +1,180 @@
+package avro
+
+import (
+	"bytes"
+	"errors"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/hamba/avro/v2"
+	"github.com/ieshan/timi"
+)
+
+func TestEncodingSchema(t *testing.T) {
+	enc := Encoding{LogicalType: TimestampMillis}
+	if enc.Schema() != `["null",{"type":"long","logicalType":"timestamp-millis"}]` {
+		t.Fatalf("Unexpected schema %s", enc.Schema())
+	}
+	expected := `{"name":"end_time","type":["null",{"type":"long","logicalType":"timestamp-millis"}],"default":null}`
+	if enc.SchemaField("end_time") != expected {
+		t.Fatalf("Expected %s, got %s", expected, enc.SchemaField("end_time"))
+	}
+
+	for _, lt := range []LogicalType{TimestampMillis, TimestampMicros, LocalTimestampMillis, LocalTimestampMicros} {
+		schema := `{"type":"record","name":"Event","fields":[` + Encoding{LogicalType: lt}.SchemaField("t") + `]}`
+		if _, err := avro.Parse(schema); err != nil {
+			t.Fatalf("%s: generated schema does not parse: %v", lt, err)
+		}
+	}
+}
+
+func TestEncodingRounding(t *testing.T) {
+	ti := timi.Date(2021, 1, 1, 0, 0, 0, 1500500, time.UTC)
+	testCases := []struct {
+		lt       LogicalType
+		rounding Rounding
+		expected int64
+		hasErr   bool
+	}{
+		{TimestampMillis, RoundError, 0, true},
+		{TimestampMillis, RoundTruncate, 1609459200001, false},
+		{TimestampMillis, RoundNearest, 1609459200002, false},
+		{TimestampMicros, RoundError, 0, true},
+		{TimestampMicros, RoundTruncate, 1609459200001500, false},
+		{TimestampMicros, RoundNearest, 1609459200001501, false},
+	}
+	for _, tc := range testCases {
+		v, err := Encoding{LogicalType: tc.lt, Rounding: tc.rounding}.Long(ti)
+		if tc.hasErr {
+			if !errors.Is(err, ErrPrecisionLoss) {
+				t.Fatalf("%s/%d: expected ErrPrecisionLoss, got %v", tc.lt, tc.rounding, err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("%s/%d: Long failed: %v", tc.lt, tc.rounding, err)
+		}
+		if *v != tc.expected {
+			t.Fatalf("%s/%d: expected %d, got %d", tc.lt, tc.rounding, tc.expected, *v)
+		}
+	}
+
+	exact := timi.Date(2021, 1, 1, 0, 0, 0, 1000000, time.UTC)
+	if _, err := (Encoding{LogicalType: TimestampMillis}).Long(exact); err != nil {
+		t.Fatalf("Exact millisecond value should encode without error, got %v", err)
+	}
+	if _, err := (Encoding{LogicalType: "date"}).Long(exact); err == nil {
+		t.Fatalf("Expecting error for unsupported logical type, but got nil")
+	}
+}
+
+func TestEncodingNull(t *testing.T) {
+	enc := Encoding{LogicalType: TimestampMicros}
+	v, err := enc.Long(timi.NilTime)
+	if err != nil || v != nil {
+		t.Fatalf("Expected nil long for null time, got %v (%v)", v, err)
+	}
+	back, err := enc.Time(nil)
+	if err != nil || !back.IsNull() {
+		t.Fatalf("Expected null time for nil long, got %v (%v)", back, err)
+	}
+}
+
+func TestEncodingInfinity(t *testing.T) {
+	testCases := []struct {
+		time     timi.Time
+		expected int64
+	}{
+		{timi.Infinity, math.MaxInt64},
+		{timi.NegInfinity, math.MinInt64},
+	}
+	for _, lt := range []LogicalType{TimestampMillis, TimestampMicros, LocalTimestampMillis, LocalTimestampMicros} {
+		enc := Encoding{LogicalType: lt, Location: time.FixedZone("", 3600)}
+		for _, tc := range testCases {
+			v, err := enc.Long(tc.time)
+			if err != nil || v == nil || *v != tc.expected {
+				t.Fatalf("%s: expected %d for %v, got %v (%v)", lt, tc.expected, tc.time, v, err)
+			}
+			back, err := enc.Time(v)
+			if err != nil || back != tc.time {
+				t.Fatalf("%s: expected %v, got %v (%v)", lt, tc.time, back, err)
+			}
+		}
+	}
+}
+
+func TestEncodingLocalTimestamp(t *testing.T) {
+	loc := time.FixedZone("UTC+9", 9*3600)
+	enc := Encoding{LogicalType: LocalTimestampMillis, Location: loc}
+	ti := timi.Date(2021, 1, 1, 9, 0, 0, 0, loc) // 00:00 UTC, 09:00 wall clock
+	v, err := enc.Long(ti)
+	if err != nil {
+		t.Fatalf("Long failed: %v", err)
+	}
+	wall := time.Date(2021, 1, 1, 9, 0, 0, 0, time.UTC).UnixMilli()
+	if *v != wall {
+		t.Fatalf("Expected wall clock %d, got %d", wall, *v)
+	}
+	back, err := enc.Time(v)
+	if err != nil {
+		t.Fatalf("Time failed: %v", err)
+	}
+	if !back.Equal(ti) || back.Time.Location() != time.UTC {
+		t.Fatalf("Expected %v in UTC, got %v", ti, back)
+	}
+}
+
+func TestEncodingBinaryWithHamba(t *testing.T) {
+	type Event struct {
+		EndTime *int64 `avro:"end_time"`
+	}
+	values := []timi.Time{
+		timi.Date(2021, 1, 1, 12, 30, 45, 123000000, time.UTC),
+		timi.Date(1969, 12, 31, 23, 59, 59, 0, time.UTC),
+		timi.NilTime,
+	}
+	for _, lt := range []LogicalType{TimestampMillis, TimestampMicros} {
+		enc := Encoding{LogicalType: lt}
+		schema := avro.MustParse(`{"type":"record","name":"Event","fields":[` + enc.SchemaField("end_time") + `]}`)
+		for _, ti := range values {
+			v, err := enc.Long(ti)
+			if err != nil {
+				t.Fatalf("%s: Long failed: %v", lt, err)
+			}
+			expected, err := avro.Marshal(schema, Event{EndTime: v})
+			if err != nil {
+				t.Fatalf("%s: hamba Marshal failed: %v", lt, err)
+			}
+			actual, err := enc.AppendBinary(nil, ti)
+			if err != nil {
+				t.Fatalf("%s: AppendBinary failed: %v", lt, err)
+			}
+			if !bytes.Equal(actual, expected) {
+				t.Fatalf("%s: expected bytes %x, got %x", lt, expected, actual)
+			}
+
+			var event Event
+			if err = avro.Unmarshal(schema, actual, &event); err != nil {
+				t.Fatalf("%s: hamba Unmarshal failed: %v", lt, err)
+			}
+			fromHamba, err := enc.Time(event.EndTime)
+			if err != nil {
+				t.Fatalf("%s: Time failed: %v", lt, err)
+			}
+			fromBinary, n, err := enc.ReadBinary(actual)
+			if err != nil || n != len(actual) {
+				t.Fatalf("%s: ReadBinary failed: %v (read %d of %d)", lt, err, n, len(actual))
+			}
+			for _, back := range []timi.Time{fromHamba, fromBinary} {
+				if back.IsNull() != ti.IsNull() || !back.Equal(ti) {
+					t.Fatalf("%s: round trip failed: expected %v, got %v", lt, ti, back)
+				}
+			}
+		}
+	}
+
+	if _, _, err := (Encoding{LogicalType: TimestampMillis}).ReadBinary([]byte{0x04}); err == nil {
+		t.Fatalf("Expecting error for invalid union branch, but got nil")
+	}
+}
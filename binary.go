@@ -0,0 +1,82 @@
+package timi
+
+import (
+	"encoding/binary"
+	"errors"
+	"time"
+)
+
+// Binary encoding layout.
+//
+// The first byte is the header. A null Time is encoded as the single byte
+// binaryNull. A valid Time is encoded as the format version followed by the
+// seconds since the Unix epoch as a zig-zag varint and the nanosecond offset
+// within that second as an unsigned varint. The location is not encoded since
+// timi.Time is always in UTC.
+//
+// The encoding is stable across releases, so the bytes can be used as cache
+// keys. New layouts must use a new version byte.
+const (
+	binaryNull      byte = 0
+	binaryVersionV1 byte = 1
+)
+
+// AppendBinary implements the encoding.BinaryAppender interface.
+// It appends the binary encoding of t to b and returns the extended buffer.
+func (t Time) AppendBinary(b []byte) ([]byte, error) {
+	if !t.Valid {
+		return append(b, binaryNull), nil
+	}
+	b = append(b, binaryVersionV1)
+	b = binary.AppendVarint(b, t.Time.Unix())
+	b = binary.AppendUvarint(b, uint64(t.Time.Nanosecond()))
+	return b, nil
+}
+
+// MarshalBinary implements the encoding.BinaryMarshaler interface.
+func (t Time) MarshalBinary() ([]byte, error) {
+	return t.AppendBinary(make([]byte, 0, 1+binary.MaxVarintLen64+binary.MaxVarintLen32))
+}
+
+// UnmarshalBinary implements the encoding.BinaryUnmarshaler interface.
+func (t *Time) UnmarshalBinary(data []byte) error {
+	if len(data) == 0 {
+		return errors.New("timi.Time.UnmarshalBinary: no data")
+	}
+	switch data[0] {
+	case binaryNull:
+		if len(data) != 1 {
+			return errors.New("timi.Time.UnmarshalBinary: invalid length")
+		}
+		t.Time, t.Valid = time.Time{}, false
+		return nil
+	case binaryVersionV1:
+	default:
+		return errors.New("timi.Time.UnmarshalBinary: unsupported version")
+	}
+	data = data[1:]
+	sec, n := binary.Varint(data)
+	if n <= 0 {
+		return errors.New("timi.Time.UnmarshalBinary: invalid seconds")
+	}
+	data = data[n:]
+	nsec, n := binary.Uvarint(data)
+	if n <= 0 || nsec >= uint64(time.Second) {
+		return errors.New("timi.Time.UnmarshalBinary: invalid nanoseconds")
+	}
+	if len(data) != n {
+		return errors.New("timi.Time.UnmarshalBinary: invalid length")
+	}
+	t.Time, t.Valid = time.Unix(sec, int64(nsec)).UTC(), true
+	return nil
+}
+
+// GobEncode implements the gob.GobEncoder interface.
+func (t Time) GobEncode() ([]byte, error) {
+	return t.MarshalBinary()
+}
+
+// GobDecode implements the gob.GobDecoder interface.
+func (t *Time) GobDecode(data []byte) error {
+	return t.UnmarshalBinary(data)
+}
@@ -0,0 +1,370 @@
+// Package bitemporal keeps history tables, where each row is a version of an
+// entity valid over a period and recorded at a transaction time:
+//
+//	CREATE TABLE prices (
+//		id          BIGINT PRIMARY KEY,
+//		product_id  BIGINT NOT NULL,
+//		amount      NUMERIC NOT NULL,
+//		valid_from  TIMESTAMPTZ,          -- null: since ever
+//		valid_to    TIMESTAMPTZ,          -- null: current
+//		recorded_at TIMESTAMPTZ NOT NULL
+//	);
+//
+// Period is the valid time of a version, the half-open interval
+// [valid_from, valid_to). Columns renders predicates over such a table, such
+// as "as of valid time X, known at transaction time Y", as sqlfilter
+// predicates. Insert and Close compute the writes that keep the versions of
+// an entity from overlapping when a new version is recorded. They update
+// versions in place, which loses what was known before; tables with a
+// superseded_at column keep it with InsertSuperseding and CloseSuperseding:
+//
+//	changes, err := bitemporal.Insert(history, bitemporal.Version[Price]{
+//		Period:     bitemporal.Period{From: effective},
+//		RecordedAt: timi.Now(),
+//		Value:      price,
+//	})
+//
+//	p := bitemporal.DefaultColumns.AsOf(validAt, knownAt)
+//	db.Where("product_id = ?", id).Where(p.String(), p.Args()...).Find(&prices)
+package bitemporal
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/ieshan/timi"
+	"github.com/ieshan/timi/sqlfilter"
+)
+
+var (
+	// ErrEmptyPeriod is returned for a period that does not end after it
+	// starts.
+	ErrEmptyPeriod = errors.New("timi/bitemporal: period is empty")
+	// ErrOverlap is returned for a history with overlapping versions.
+	ErrOverlap = errors.New("timi/bitemporal: periods overlap")
+	// ErrOutsidePeriod is returned when splitting or closing a period at a
+	// time it does not contain.
+	ErrOutsidePeriod = errors.New("timi/bitemporal: time is outside the period")
+	// ErrRecordedBefore is returned for a version recorded before a version
+	// it replaces; transaction time only moves forward.
+	ErrRecordedBefore = errors.New("timi/bitemporal: version is recorded before the versions it replaces")
+	// ErrNotRecorded is returned when superseding versions without a
+	// transaction time to supersede them at.
+	ErrNotRecorded = errors.New("timi/bitemporal: transaction time is null")
+)
+
+// Period is the half-open interval [From, To) of valid time. A null From is
+// unbounded in the past and a null To, the current version, in the future.
+// Infinity and NegInfinity are unbounded as well.
+type Period struct {
+	From timi.Time
+	To   timi.Time
+}
+
+// start returns the start of p, NegInfinity when unbounded.
+func (p Period) start() timi.Time {
+	if !p.From.Valid {
+		return timi.NegInfinity
+	}
+	return p.From
+}
+
+// end returns the end of p, Infinity when unbounded.
+func (p Period) end() timi.Time {
+	if !p.To.Valid {
+		return timi.Infinity
+	}
+	return p.To
+}
+
+// AsOf reports whether p contains the valid time t. A null t is in no
+// period.
+func (p Period) AsOf(t timi.Time) bool {
+	return t.Valid && !t.Before(p.start()) && t.Before(p.end())
+}
+
+// Current reports whether p has no end: To is null or Infinity.
+func (p Period) Current() bool {
+	return !p.To.Valid || p.To.IsInfinity()
+}
+
+// IsEmpty reports whether p does not end after it starts.
+func (p Period) IsEmpty() bool {
+	return !p.start().Before(p.end())
+}
+
+// Overlaps reports whether p and q share a valid time. Adjacent periods,
+// where one ends when the other starts, do not overlap.
+func (p Period) Overlaps(q Period) bool {
+	return p.start().Before(q.end()) && q.start().Before(p.end())
+}
+
+// Split returns the parts of p before and from at. It returns
+// ErrOutsidePeriod unless at is strictly inside p.
+func (p Period) Split(at timi.Time) (Period, Period, error) {
+	if !at.Valid || !p.start().Before(at) || !at.Before(p.end()) {
+		return Period{}, Period{}, fmt.Errorf("%w: %v in %v", ErrOutsidePeriod, at, p)
+	}
+	return Period{From: p.From, To: at}, Period{From: at, To: p.To}, nil
+}
+
+// Close returns p ending at at. It returns ErrOutsidePeriod unless at is
+// after the start of p and not after its end.
+func (p Period) Close(at timi.Time) (Period, error) {
+	if !at.Valid || !p.start().Before(at) || p.end().Before(at) {
+		return Period{}, fmt.Errorf("%w: %v in %v", ErrOutsidePeriod, at, p)
+	}
+	return Period{From: p.From, To: at}, nil
+}
+
+// String returns p as [From, To), with null ends written as -infinity and
+// infinity.
+func (p Period) String() string {
+	return "[" + p.start().String() + ", " + p.end().String() + ")"
+}
+
+// Columns names the period and transaction time columns of a history table.
+type Columns struct {
+	ValidFrom  string
+	ValidTo    string
+	RecordedAt string
+	// SupersededAt is the column, if any, that ends the transaction time of
+	// a version, for tables that keep replaced versions instead of updating
+	// them. Without it, KnownAt only excludes versions recorded later.
+	SupersededAt string
+}
+
+// DefaultColumns are valid_from, valid_to and recorded_at.
+var DefaultColumns = Columns{ValidFrom: "valid_from", ValidTo: "valid_to", RecordedAt: "recorded_at"}
+
+// ValidAt matches versions whose period contains t. A null t matches every
+// version.
+func (c Columns) ValidAt(t timi.Time) sqlfilter.Predicate {
+	if !t.Valid {
+		return sqlfilter.And()
+	}
+	return sqlfilter.And(
+		sqlfilter.Or(sqlfilter.IsNull(c.ValidFrom), sqlfilter.Between(c.ValidFrom, timi.NilTime, t, sqlfilter.OpenClosed)),
+		sqlfilter.Or(sqlfilter.IsNull(c.ValidTo), sqlfilter.After(c.ValidTo, t)),
+	)
+}
+
+// KnownAt matches versions recorded at or before t and, with SupersededAt,
+// not superseded by then. A null t matches the versions known now: every
+// version, or those not superseded.
+func (c Columns) KnownAt(t timi.Time) sqlfilter.Predicate {
+	if !t.Valid {
+		if c.SupersededAt == "" {
+			return sqlfilter.And()
+		}
+		return sqlfilter.IsNull(c.SupersededAt)
+	}
+	p := sqlfilter.Between(c.RecordedAt, timi.NilTime, t, sqlfilter.OpenClosed)
+	if c.SupersededAt == "" {
+		return p
+	}
+	return sqlfilter.And(p, sqlfilter.Or(sqlfilter.IsNull(c.SupersededAt), sqlfilter.After(c.SupersededAt, t)))
+}
+
+// AsOf matches versions valid at valid, as known at known; see ValidAt and
+// KnownAt for null times.
+func (c Columns) AsOf(valid, known timi.Time) sqlfilter.Predicate {
+	return sqlfilter.And(c.ValidAt(valid), c.KnownAt(known))
+}
+
+// Current matches versions with a null ValidTo, as Insert and Close leave
+// the current version.
+func (c Columns) Current() sqlfilter.Predicate {
+	return sqlfilter.IsNull(c.ValidTo)
+}
+
+// Overlapping matches versions whose period overlaps p, such as the
+// versions to load before calling Insert.
+func (c Columns) Overlapping(p Period) sqlfilter.Predicate {
+	return sqlfilter.Overlaps(c.ValidFrom, c.ValidTo, p.From, p.To)
+}
+
+// Version is a row of a history table: a value valid over a period,
+// recorded at a transaction time and, once replaced, superseded at a later
+// one.
+type Version[T any] struct {
+	Period
+	RecordedAt   timi.Time
+	SupersededAt timi.Time
+	Value        T
+}
+
+// Changes are the writes that apply Insert or Close to a history.
+type Changes[T any] struct {
+	// Updated are existing versions with a shortened period; write their
+	// valid_from and valid_to.
+	Updated []Version[T]
+	// Deleted are existing versions the change covers entirely.
+	Deleted []Version[T]
+	// Superseded are existing versions the change replaces, with their
+	// SupersededAt set; write their superseded_at.
+	Superseded []Version[T]
+	// Inserted are the new version and the parts of the versions it
+	// shortens or splits that are recorded as new rows.
+	Inserted []Version[T]
+	// History is the resulting history, ordered by valid_from, without
+	// superseded versions.
+	History []Version[T]
+}
+
+// Validate returns ErrEmptyPeriod or ErrOverlap unless the periods of
+// history, in any order, are non-empty and do not overlap. Superseded
+// versions are ignored.
+func Validate[T any](history []Version[T]) error {
+	_, err := sorted(history)
+	return err
+}
+
+// sorted returns the versions of history that are not superseded, ordered by
+// valid_from, and validates them.
+func sorted[T any](history []Version[T]) ([]Version[T], error) {
+	var vs []Version[T]
+	for _, v := range history {
+		if !v.SupersededAt.Valid {
+			vs = append(vs, v)
+		}
+	}
+	sort.SliceStable(vs, func(i, j int) bool {
+		return vs[i].start().Before(vs[j].start())
+	})
+	for i, v := range vs {
+		if v.IsEmpty() {
+			return nil, fmt.Errorf("%w: %v", ErrEmptyPeriod, v.Period)
+		}
+		if i > 0 && vs[i-1].Overlaps(v.Period) {
+			return nil, fmt.Errorf("%w: %v and %v", ErrOverlap, vs[i-1].Period, v.Period)
+		}
+	}
+	return vs, nil
+}
+
+// Insert records v in history, the versions of one entity. Versions that v
+// overlaps are shortened to the valid time outside v; a version that extends
+// on both sides of v is split, and its later part is inserted with the
+// original RecordedAt. v may not be recorded before a version it replaces.
+//
+// The shortened versions are updated in place, so the periods they were
+// recorded with are lost: KnownAt a time before v.RecordedAt no longer
+// matches them over the valid time of v. Use InsertSuperseding to keep
+// transaction time history.
+func Insert[T any](history []Version[T], v Version[T]) (Changes[T], error) {
+	return insert(history, v, false)
+}
+
+// InsertSuperseding records v in history like Insert, for tables with a
+// SupersededAt column. Instead of updating the versions v overlaps, it
+// supersedes them at v.RecordedAt and inserts their parts outside v as new
+// versions recorded at v.RecordedAt, so KnownAt an earlier time still
+// matches them as they were. Superseded versions in history are ignored.
+func InsertSuperseding[T any](history []Version[T], v Version[T]) (Changes[T], error) {
+	if !v.RecordedAt.Valid {
+		return Changes[T]{}, ErrNotRecorded
+	}
+	return insert(history, v, true)
+}
+
+func insert[T any](history []Version[T], v Version[T], supersede bool) (Changes[T], error) {
+	if v.IsEmpty() {
+		return Changes[T]{}, fmt.Errorf("%w: %v", ErrEmptyPeriod, v.Period)
+	}
+	changes, err := cut(history, v.Period, v.RecordedAt, supersede)
+	if err != nil {
+		return Changes[T]{}, err
+	}
+	changes.Inserted = append(changes.Inserted, v)
+	changes.History = append(changes.History, v)
+	sort.SliceStable(changes.History, func(i, j int) bool {
+		return changes.History[i].start().Before(changes.History[j].start())
+	})
+	return changes, nil
+}
+
+// Close ends the valid time of history, the versions of one entity, at at:
+// the version current at at ends then, and later versions are deleted. Like
+// Insert, it updates versions in place.
+func Close[T any](history []Version[T], at timi.Time) (Changes[T], error) {
+	if !at.Valid {
+		return Changes[T]{}, fmt.Errorf("%w: %v", ErrOutsidePeriod, at)
+	}
+	return cut(history, Period{From: at}, timi.NilTime, false)
+}
+
+// CloseSuperseding ends the valid time of history at at like Close, for
+// tables with a SupersededAt column: the versions it changes are superseded
+// at recordedAt, and the version current at at is inserted again, recorded
+// at recordedAt and ending at at.
+func CloseSuperseding[T any](history []Version[T], at, recordedAt timi.Time) (Changes[T], error) {
+	if !at.Valid {
+		return Changes[T]{}, fmt.Errorf("%w: %v", ErrOutsidePeriod, at)
+	}
+	if !recordedAt.Valid {
+		return Changes[T]{}, ErrNotRecorded
+	}
+	return cut(history, Period{From: at}, recordedAt, true)
+}
+
+// cut removes the valid time p from history, updating versions in place or,
+// with supersede, superseding them at recordedAt. A valid recordedAt may not
+// be before the versions it changes.
+func cut[T any](history []Version[T], p Period, recordedAt timi.Time, supersede bool) (Changes[T], error) {
+	vs, err := sorted(history)
+	if err != nil {
+		return Changes[T]{}, err
+	}
+	var changes Changes[T]
+	for _, old := range vs {
+		if !old.Overlaps(p) {
+			changes.History = append(changes.History, old)
+			continue
+		}
+		if recordedAt.Valid && old.RecordedAt.Valid && recordedAt.Before(old.RecordedAt) {
+			return Changes[T]{}, fmt.Errorf("%w: %v is before %v", ErrRecordedBefore, recordedAt, old.RecordedAt)
+		}
+		head := old.start().Before(p.start())
+		tail := p.end().Before(old.end())
+		if supersede {
+			kept := old
+			kept.RecordedAt = recordedAt
+			if head {
+				before := kept
+				before.To = p.From
+				changes.Inserted = append(changes.Inserted, before)
+				changes.History = append(changes.History, before)
+			}
+			if tail {
+				after := kept
+				after.From = p.To
+				changes.Inserted = append(changes.Inserted, after)
+				changes.History = append(changes.History, after)
+			}
+			old.SupersededAt = recordedAt
+			changes.Superseded = append(changes.Superseded, old)
+			continue
+		}
+		switch {
+		case head && tail:
+			before, after := old, old
+			before.To, after.From = p.From, p.To
+			changes.Updated = append(changes.Updated, before)
+			changes.Inserted = append(changes.Inserted, after)
+			changes.History = append(changes.History, before, after)
+		case head:
+			old.To = p.From
+			changes.Updated = append(changes.Updated, old)
+			changes.History = append(changes.History, old)
+		case tail:
+			old.From = p.To
+			changes.Updated = append(changes.Updated, old)
+			changes.History = append(changes.History, old)
+		default:
+			changes.Deleted = append(changes.Deleted, old)
+		}
+	}
+	return changes, nil
+}
@@ -0,0 +1,372 @@
+package bitemporal
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/ieshan/timi"
+)
+
+func day(d int) timi.Time {
+	return timi.Date(2024, 1, d, 0, 0, 0, 0, time.UTC)
+}
+
+func TestPeriod_AsOf(t *testing.T) {
+	testCases := []struct {
+		name     string
+		p        Period
+		t        timi.Time
+		expected bool
+	}{
+		{"Inside", Period{day(1), day(10)}, day(5), true},
+		{"From", Period{day(1), day(10)}, day(1), true},
+		{"To", Period{day(1), day(10)}, day(10), false},
+		{"Before", Period{day(1), day(10)}, day(1).Add(-time.Nanosecond), false},
+		{"Current", Period{day(1), timi.NilTime}, day(31), true},
+		{"Infinity", Period{day(1), timi.Infinity}, day(31), true},
+		{"SinceEver", Period{timi.NilTime, day(10)}, timi.Date(1900, 1, 1, 0, 0, 0, 0, time.UTC), true},
+		{"NullTime", Period{}, timi.NilTime, false},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := tc.p.AsOf(tc.t); got != tc.expected {
+				t.Fatalf("Expected %v, got %v", tc.expected, got)
+			}
+		})
+	}
+}
+
+func TestPeriod_Current(t *testing.T) {
+	if !(Period{From: day(1)}).Current() || !(Period{day(1), timi.Infinity}).Current() {
+		t.Fatalf("Expected periods without an end to be current")
+	}
+	if (Period{day(1), day(2)}).Current() {
+		t.Fatalf("Expected a closed period not to be current")
+	}
+}
+
+func TestPeriod_Overlaps(t *testing.T) {
+	testCases := []struct {
+		name     string
+		p, q     Period
+		expected bool
+	}{
+		{"Overlapping", Period{day(1), day(10)}, Period{day(5), day(15)}, true},
+		{"Adjacent", Period{day(1), day(10)}, Period{day(10), day(15)}, false},
+		{"Disjoint", Period{day(1), day(5)}, Period{day(10), day(15)}, false},
+		{"Contained", Period{day(1), day(10)}, Period{day(3), day(4)}, true},
+		{"Current", Period{day(1), timi.NilTime}, Period{day(20), day(21)}, true},
+		{"Unbounded", Period{}, Period{day(1), day(2)}, true},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := tc.p.Overlaps(tc.q); got != tc.expected {
+				t.Fatalf("Expected %v, got %v", tc.expected, got)
+			}
+			if got := tc.q.Overlaps(tc.p); got != tc.expected {
+				t.Fatalf("Expected %v reversed, got %v", tc.expected, got)
+			}
+		})
+	}
+}
+
+func TestPeriod_SplitClose(t *testing.T) {
+	p := Period{From: day(1)}
+	before, after, err := p.Split(day(10))
+	if err != nil || before != (Period{day(1), day(10)}) || after != (Period{From: day(10)}) {
+		t.Fatalf("Unexpected split %v, %v, %v", before, after, err)
+	}
+	for _, at := range []timi.Time{day(1), timi.NilTime, timi.Date(2023, 12, 31, 0, 0, 0, 0, time.UTC)} {
+		if _, _, err = p.Split(at); !errors.Is(err, ErrOutsidePeriod) {
+			t.Fatalf("Split %v: expected ErrOutsidePeriod, got %v", at, err)
+		}
+	}
+
+	closed, err := p.Close(day(10))
+	if err != nil || closed != (Period{day(1), day(10)}) {
+		t.Fatalf("Unexpected close %v, %v", closed, err)
+	}
+	if closed, err = closed.Close(day(10)); err != nil || closed != (Period{day(1), day(10)}) {
+		t.Fatalf("Expected closing at the end to keep the period, got %v, %v", closed, err)
+	}
+	if _, err = closed.Close(day(11)); !errors.Is(err, ErrOutsidePeriod) {
+		t.Fatalf("Expected ErrOutsidePeriod when extending, got %v", err)
+	}
+	if _, err = closed.Close(day(1)); !errors.Is(err, ErrOutsidePeriod) {
+		t.Fatalf("Expected ErrOutsidePeriod for an empty period, got %v", err)
+	}
+}
+
+func TestPeriod_String(t *testing.T) {
+	expected := "[2024-01-01 00:00:00 +0000 UTC, infinity)"
+	if s := (Period{From: day(1)}).String(); s != expected {
+		t.Fatalf("Expected %s, got %s", expected, s)
+	}
+}
+
+func TestColumns(t *testing.T) {
+	c := DefaultColumns
+	versioned := Columns{ValidFrom: "valid_from", ValidTo: "valid_to", RecordedAt: "recorded_at", SupersededAt: "superseded_at"}
+	testCases := []struct {
+		name string
+		sql  string
+		args []any
+		got  interface {
+			String() string
+			Args() []any
+		}
+	}{
+		{"ValidAt", "(valid_from IS NULL OR valid_from <= ?) AND (valid_to IS NULL OR valid_to > ?)", []any{day(5), day(5)}, c.ValidAt(day(5))},
+		{"ValidAtNull", "1 = 1", nil, c.ValidAt(timi.NilTime)},
+		{"KnownAt", "recorded_at <= ?", []any{day(7)}, c.KnownAt(day(7))},
+		{"KnownNow", "1 = 1", nil, c.KnownAt(timi.NilTime)},
+		{"KnownAtSuperseded", "recorded_at <= ? AND (superseded_at IS NULL OR superseded_at > ?)", []any{day(7), day(7)}, versioned.KnownAt(day(7))},
+		{"KnownNowSuperseded", "superseded_at IS NULL", nil, versioned.KnownAt(timi.NilTime)},
+		{"AsOf", "(valid_from IS NULL OR valid_from <= ?) AND (valid_to IS NULL OR valid_to > ?) AND recorded_at <= ?",
+			[]any{day(5), day(5), day(7)}, c.AsOf(day(5), day(7))},
+		{"Current", "valid_to IS NULL", nil, c.Current()},
+		{"Overlapping", "(valid_from IS NULL OR valid_from < ?) AND (valid_to IS NULL OR valid_to > ?)",
+			[]any{day(10), day(1)}, c.Overlapping(Period{day(1), day(10)})},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if sql := tc.got.String(); sql != tc.sql {
+				t.Fatalf("Expected %s, got %s", tc.sql, sql)
+			}
+			if args := tc.got.Args(); !reflect.DeepEqual(args, tc.args) {
+				t.Fatalf("Expected %v, got %v", tc.args, args)
+			}
+		})
+	}
+}
+
+func version(value string, from, to timi.Time, recordedAt timi.Time) Version[string] {
+	return Version[string]{Period: Period{from, to}, RecordedAt: recordedAt, Value: value}
+}
+
+func superseded(v Version[string], at timi.Time) Version[string] {
+	v.SupersededAt = at
+	return v
+}
+
+func TestValidate(t *testing.T) {
+	history := []Version[string]{
+		version("b", day(10), timi.NilTime, day(10)),
+		version("a", timi.NilTime, day(10), day(1)),
+	}
+	if err := Validate(history); err != nil {
+		t.Fatalf("Expected adjacent versions to be valid, got %v", err)
+	}
+	history = append(history, version("c", day(20), day(21), day(20)))
+	if err := Validate(history); !errors.Is(err, ErrOverlap) {
+		t.Fatalf("Expected ErrOverlap, got %v", err)
+	}
+	if err := Validate([]Version[string]{version("a", day(2), day(2), day(1))}); !errors.Is(err, ErrEmptyPeriod) {
+		t.Fatalf("Expected ErrEmptyPeriod, got %v", err)
+	}
+	history[2] = superseded(history[2], day(21))
+	if err := Validate(history); err != nil {
+		t.Fatalf("Expected superseded versions to be ignored, got %v", err)
+	}
+}
+
+func TestInsert(t *testing.T) {
+	history := []Version[string]{
+		version("b", day(10), timi.NilTime, day(10)),
+		version("a", day(1), day(10), day(1)),
+	}
+	testCases := []struct {
+		name     string
+		v        Version[string]
+		updated  []Version[string]
+		deleted  []Version[string]
+		inserted []Version[string]
+		history  []Version[string]
+	}{
+		{
+			name:     "NewCurrent",
+			v:        version("c", day(20), timi.NilTime, day(20)),
+			updated:  []Version[string]{version("b", day(10), day(20), day(10))},
+			inserted: []Version[string]{version("c", day(20), timi.NilTime, day(20))},
+			history: []Version[string]{
+				version("a", day(1), day(10), day(1)),
+				version("b", day(10), day(20), day(10)),
+				version("c", day(20), timi.NilTime, day(20)),
+			},
+		},
+		{
+			name:    "Correction",
+			v:       version("x", day(5), day(15), day(20)),
+			updated: []Version[string]{version("a", day(1), day(5), day(1)), version("b", day(15), timi.NilTime, day(10))},
+			inserted: []Version[string]{
+				version("x", day(5), day(15), day(20)),
+			},
+			history: []Version[string]{
+				version("a", day(1), day(5), day(1)),
+				version("x", day(5), day(15), day(20)),
+				version("b", day(15), timi.NilTime, day(10)),
+			},
+		},
+		{
+			name:    "Split",
+			v:       version("x", day(3), day(4), day(20)),
+			updated: []Version[string]{version("a", day(1), day(3), day(1))},
+			inserted: []Version[string]{
+				version("a", day(4), day(10), day(1)),
+				version("x", day(3), day(4), day(20)),
+			},
+			history: []Version[string]{
+				version("a", day(1), day(3), day(1)),
+				version("x", day(3), day(4), day(20)),
+				version("a", day(4), day(10), day(1)),
+				version("b", day(10), timi.NilTime, day(10)),
+			},
+		},
+		{
+			name:     "Replace",
+			v:        version("x", day(1), timi.NilTime, day(20)),
+			deleted:  []Version[string]{version("a", day(1), day(10), day(1)), version("b", day(10), timi.NilTime, day(10))},
+			inserted: []Version[string]{version("x", day(1), timi.NilTime, day(20))},
+			history:  []Version[string]{version("x", day(1), timi.NilTime, day(20))},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			changes, err := Insert(history, tc.v)
+			if err != nil {
+				t.Fatalf("Insert failed: %v", err)
+			}
+			if !reflect.DeepEqual(changes.Updated, tc.updated) {
+				t.Fatalf("Updated: expected %v, got %v", tc.updated, changes.Updated)
+			}
+			if !reflect.DeepEqual(changes.Deleted, tc.deleted) {
+				t.Fatalf("Deleted: expected %v, got %v", tc.deleted, changes.Deleted)
+			}
+			if !reflect.DeepEqual(changes.Inserted, tc.inserted) {
+				t.Fatalf("Inserted: expected %v, got %v", tc.inserted, changes.Inserted)
+			}
+			if !reflect.DeepEqual(changes.History, tc.history) {
+				t.Fatalf("History: expected %v, got %v", tc.history, changes.History)
+			}
+			if err = Validate(changes.History); err != nil {
+				t.Fatalf("Expected a valid history, got %v", err)
+			}
+		})
+	}
+
+	if _, err := Insert(history, version("x", day(5), day(5), day(20))); !errors.Is(err, ErrEmptyPeriod) {
+		t.Fatalf("Expected ErrEmptyPeriod, got %v", err)
+	}
+	if _, err := Insert(history, version("x", day(12), timi.NilTime, day(5))); !errors.Is(err, ErrRecordedBefore) {
+		t.Fatalf("Expected ErrRecordedBefore, got %v", err)
+	}
+	overlapping := append(history, version("c", day(5), day(6), day(5)))
+	if _, err := Insert(overlapping, version("x", day(20), timi.NilTime, day(20))); !errors.Is(err, ErrOverlap) {
+		t.Fatalf("Expected ErrOverlap, got %v", err)
+	}
+}
+
+func TestInsertSuperseding(t *testing.T) {
+	history := []Version[string]{
+		version("b", day(10), timi.NilTime, day(10)),
+		version("a", day(1), day(10), day(1)),
+		superseded(version("old", day(1), timi.NilTime, day(1)), day(10)),
+	}
+	changes, err := InsertSuperseding(history, version("x", day(3), day(4), day(20)))
+	if err != nil {
+		t.Fatalf("InsertSuperseding failed: %v", err)
+	}
+	if len(changes.Updated) != 0 || len(changes.Deleted) != 0 {
+		t.Fatalf("Expected no updates or deletes, got %+v", changes)
+	}
+	// The split version is kept as it was known until the correction
+	if expected := []Version[string]{superseded(version("a", day(1), day(10), day(1)), day(20))}; !reflect.DeepEqual(changes.Superseded, expected) {
+		t.Fatalf("Superseded: expected %v, got %v", expected, changes.Superseded)
+	}
+	inserted := []Version[string]{
+		version("a", day(1), day(3), day(20)),
+		version("a", day(4), day(10), day(20)),
+		version("x", day(3), day(4), day(20)),
+	}
+	if !reflect.DeepEqual(changes.Inserted, inserted) {
+		t.Fatalf("Inserted: expected %v, got %v", inserted, changes.Inserted)
+	}
+	expected := []Version[string]{
+		version("a", day(1), day(3), day(20)),
+		version("x", day(3), day(4), day(20)),
+		version("a", day(4), day(10), day(20)),
+		version("b", day(10), timi.NilTime, day(10)),
+	}
+	if !reflect.DeepEqual(changes.History, expected) {
+		t.Fatalf("History: expected %v, got %v", expected, changes.History)
+	}
+
+	changes, err = InsertSuperseding(history, version("x", day(1), timi.NilTime, day(20)))
+	if err != nil {
+		t.Fatalf("InsertSuperseding failed: %v", err)
+	}
+	if len(changes.Superseded) != 2 || len(changes.Inserted) != 1 || len(changes.History) != 1 {
+		t.Fatalf("Expected both versions superseded, got %+v", changes)
+	}
+
+	if _, err = InsertSuperseding(history, version("x", day(12), timi.NilTime, timi.NilTime)); !errors.Is(err, ErrNotRecorded) {
+		t.Fatalf("Expected ErrNotRecorded, got %v", err)
+	}
+	if _, err = InsertSuperseding(history, version("x", day(12), timi.NilTime, day(5))); !errors.Is(err, ErrRecordedBefore) {
+		t.Fatalf("Expected ErrRecordedBefore, got %v", err)
+	}
+}
+
+func TestClose(t *testing.T) {
+	history := []Version[string]{
+		version("a", day(1), day(10), day(1)),
+		version("b", day(10), day(20), day(10)),
+		version("c", day(20), timi.NilTime, day(20)),
+	}
+	changes, err := Close(history, day(15))
+	if err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+	if expected := []Version[string]{version("b", day(10), day(15), day(10))}; !reflect.DeepEqual(changes.Updated, expected) {
+		t.Fatalf("Updated: expected %v, got %v", expected, changes.Updated)
+	}
+	if expected := []Version[string]{version("c", day(20), timi.NilTime, day(20))}; !reflect.DeepEqual(changes.Deleted, expected) {
+		t.Fatalf("Deleted: expected %v, got %v", expected, changes.Deleted)
+	}
+	if len(changes.Inserted) != 0 || len(changes.History) != 2 || changes.History[1].Current() {
+		t.Fatalf("Unexpected changes %+v", changes)
+	}
+	if _, err = Close(history, timi.NilTime); !errors.Is(err, ErrOutsidePeriod) {
+		t.Fatalf("Expected ErrOutsidePeriod, got %v", err)
+	}
+}
+
+func TestCloseSuperseding(t *testing.T) {
+	history := []Version[string]{
+		version("a", day(1), day(10), day(1)),
+		version("b", day(10), day(20), day(10)),
+		version("c", day(20), timi.NilTime, day(20)),
+	}
+	changes, err := CloseSuperseding(history, day(15), day(25))
+	if err != nil {
+		t.Fatalf("CloseSuperseding failed: %v", err)
+	}
+	expected := []Version[string]{
+		superseded(version("b", day(10), day(20), day(10)), day(25)),
+		superseded(version("c", day(20), timi.NilTime, day(20)), day(25)),
+	}
+	if !reflect.DeepEqual(changes.Superseded, expected) {
+		t.Fatalf("Superseded: expected %v, got %v", expected, changes.Superseded)
+	}
+	if expected := []Version[string]{version("b", day(10), day(15), day(25))}; !reflect.DeepEqual(changes.Inserted, expected) {
+		t.Fatalf("Inserted: expected %v, got %v", expected, changes.Inserted)
+	}
+	if len(changes.Updated) != 0 || len(changes.Deleted) != 0 || len(changes.History) != 2 {
+		t.Fatalf("Unexpected changes %+v", changes)
+	}
+	if _, err = CloseSuperseding(history, day(15), timi.NilTime); !errors.Is(err, ErrNotRecorded) {
+		t.Fatalf("Expected ErrNotRecorded, got %v", err)
+	}
+}
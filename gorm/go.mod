@@ -0,0 +1,17 @@
+module github.com/ieshan/timi/gorm
+
+go 1.25.0
+
+require (
+	github.com/ieshan/timi v0.0.0
+	gorm.io/gorm v1.30.2
+)
+
+require (
+	github.com/jinzhu/inflection v1.0.0 // indirect
+	github.com/jinzhu/now v1.1.5 // indirect
+	golang.org/x/text v0.20.0 // indirect
+)
+
+// Use local timi package
+replace github.com/ieshan/timi => ../
@@ -0,0 +1,77 @@
+package gorm
+
+import (
+	"reflect"
+
+	"github.com/ieshan/timi"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"gorm.io/gorm/schema"
+)
+
+// DeletedAt is a Time that enables soft delete, like gorm.DeletedAt. Delete
+// sets it to the time of DB.NowFunc instead of removing the row, and scoped
+// queries, updates and deletes exclude rows where it is not null. Unscoped
+// skips both. Its column types are those of Time.
+type DeletedAt struct{ Time }
+
+// QueryClauses implements schema.QueryClausesInterface.
+func (DeletedAt) QueryClauses(f *schema.Field) []clause.Interface {
+	return []clause.Interface{gorm.SoftDeleteQueryClause{Field: f}}
+}
+
+// UpdateClauses implements schema.UpdateClausesInterface.
+func (DeletedAt) UpdateClauses(f *schema.Field) []clause.Interface {
+	return []clause.Interface{gorm.SoftDeleteUpdateClause{Field: f}}
+}
+
+// DeleteClauses implements schema.DeleteClausesInterface.
+func (DeletedAt) DeleteClauses(f *schema.Field) []clause.Interface {
+	return []clause.Interface{softDeleteClause{field: f}}
+}
+
+// softDeleteClause turns a delete into an update of the DeletedAt field, as
+// gorm.SoftDeleteDeleteClause does, writing the time as a DeletedAt so that
+// it is stored in the format of the dialect.
+type softDeleteClause struct {
+	field *schema.Field
+}
+
+func (sd softDeleteClause) Name() string {
+	return ""
+}
+
+func (sd softDeleteClause) Build(clause.Builder) {
+}
+
+func (sd softDeleteClause) MergeClause(*clause.Clause) {
+}
+
+func (sd softDeleteClause) ModifyStatement(stmt *gorm.Statement) {
+	if stmt.SQL.Len() > 0 || stmt.Statement.Unscoped {
+		return
+	}
+	now := DeletedAt{Time{timi.Time{Time: stmt.DB.NowFunc().UTC(), Valid: true}}}
+	stmt.AddClause(clause.Set{{Column: clause.Column{Name: sd.field.DBName}, Value: now}})
+	stmt.SetColumn(sd.field.DBName, now, true)
+
+	if stmt.Schema != nil {
+		_, queryValues := schema.GetIdentityFieldValuesMap(stmt.Context, stmt.ReflectValue, stmt.Schema.PrimaryFields)
+		column, values := schema.ToQueryValues(stmt.Table, stmt.Schema.PrimaryFieldDBNames, queryValues)
+		if len(values) > 0 {
+			stmt.AddClause(clause.Where{Exprs: []clause.Expression{clause.IN{Column: column, Values: values}}})
+		}
+
+		if stmt.ReflectValue.CanAddr() && stmt.Dest != stmt.Model && stmt.Model != nil {
+			_, queryValues = schema.GetIdentityFieldValuesMap(stmt.Context, reflect.ValueOf(stmt.Model), stmt.Schema.PrimaryFields)
+			column, values = schema.ToQueryValues(stmt.Table, stmt.Schema.PrimaryFieldDBNames, queryValues)
+			if len(values) > 0 {
+				stmt.AddClause(clause.Where{Exprs: []clause.Expression{clause.IN{Column: column, Values: values}}})
+			}
+		}
+	}
+
+	gorm.SoftDeleteQueryClause{Field: sd.field}.ModifyStatement(stmt)
+	stmt.AddClauseIfNotExists(clause.Update{})
+	stmt.Build(stmt.DB.Callback().Update().Clauses...)
+}
@@ -0,0 +1,75 @@
+package gorm
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ieshan/timi"
+	"gorm.io/gorm"
+	"gorm.io/gorm/utils/tests"
+)
+
+type softDeleteModel struct {
+	ID        int64
+	Name      string
+	CreatedAt timi.Time
+	UpdatedAt Time
+	DeletedAt DeletedAt
+}
+
+var fixedTime = timi.Date(2024, 1, 15, 10, 30, 45, 123456789, time.UTC)
+
+func newDryRunDB(t *testing.T) *gorm.DB {
+	db, err := gorm.Open(tests.DummyDialector{}, &gorm.Config{DryRun: true})
+	if err != nil {
+		t.Fatalf("Open failed: %v", err)
+	}
+	if err = db.Use(Timestamps{Clock: func() timi.Time { return fixedTime }}); err != nil {
+		t.Fatalf("Use failed: %v", err)
+	}
+	return db
+}
+
+func TestDeletedAt_Query(t *testing.T) {
+	db := newDryRunDB(t)
+	stmt := db.Where("name = ?", "a").Find(&[]softDeleteModel{}).Statement
+	expected := "SELECT * FROM `soft_delete_models` WHERE name = ? AND `soft_delete_models`.`deleted_at` IS NULL"
+	if sql := stmt.SQL.String(); sql != expected {
+		t.Fatalf("Expected %s, got %s", expected, sql)
+	}
+	stmt = db.Unscoped().Find(&[]softDeleteModel{}).Statement
+	if sql := stmt.SQL.String(); strings.Contains(sql, "deleted_at") {
+		t.Fatalf("Expected no soft delete condition, got %s", sql)
+	}
+}
+
+func TestDeletedAt_Update(t *testing.T) {
+	db := newDryRunDB(t)
+	stmt := db.Model(&softDeleteModel{ID: 1}).Update("name", "b").Statement
+	if sql := stmt.SQL.String(); !strings.Contains(sql, "WHERE `soft_delete_models`.`deleted_at` IS NULL") {
+		t.Fatalf("Expected a soft delete condition, got %s", sql)
+	}
+}
+
+func TestDeletedAt_Delete(t *testing.T) {
+	db := newDryRunDB(t)
+	model := softDeleteModel{ID: 1}
+	stmt := db.Delete(&model).Statement
+	expected := "UPDATE `soft_delete_models` SET `deleted_at`=? WHERE `soft_delete_models`.`id` = ? AND `soft_delete_models`.`deleted_at` IS NULL"
+	if sql := stmt.SQL.String(); sql != expected {
+		t.Fatalf("Expected %s, got %s", expected, sql)
+	}
+	if len(stmt.Vars) != 2 || stmt.Vars[0] != fixedTime {
+		t.Fatalf("Expected the clock time, got %v", stmt.Vars)
+	}
+	if !model.DeletedAt.Equal(fixedTime) {
+		t.Fatalf("Expected %v, got %v", fixedTime, model.DeletedAt)
+	}
+
+	stmt = db.Unscoped().Delete(&softDeleteModel{ID: 1}).Statement
+	expected = "DELETE FROM `soft_delete_models` WHERE `soft_delete_models`.`id` = ?"
+	if sql := stmt.SQL.String(); sql != expected {
+		t.Fatalf("Expected %s, got %s", expected, sql)
+	}
+}
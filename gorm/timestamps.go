@@ -0,0 +1,51 @@
+package gorm
+
+import (
+	"time"
+
+	"github.com/ieshan/timi"
+	"gorm.io/gorm"
+)
+
+// Auto Timestamps
+// GORM fills autoCreateTime and autoUpdateTime fields, CreatedAt and UpdatedAt
+// by default, of type timi.Time, Time and UnixTime by scanning the result of
+// DB.NowFunc, and DeletedAt uses it for the deletion time. Timestamps sets
+// NowFunc from a Clock, so that all of them read the same clock.
+
+// Clock returns the current time.
+type Clock func() timi.Time
+
+// NowFunc returns c as a GORM NowFunc, for gorm.Config and gorm.Session.
+func NowFunc(c Clock) func() time.Time {
+	return func() time.Time {
+		return c().Time
+	}
+}
+
+// Timestamps is a gorm.Plugin that sets the NowFunc of the DB to Clock, or to
+// timi.Now when Clock is nil:
+//
+//	db.Use(timigorm.Timestamps{})
+//	db.Use(timigorm.Timestamps{Clock: fixedClock})
+//
+// A session can use another clock with
+// db.Session(&gorm.Session{NowFunc: timigorm.NowFunc(c)}).
+type Timestamps struct {
+	Clock Clock
+}
+
+// Name implements gorm.Plugin.
+func (Timestamps) Name() string {
+	return "timi:timestamps"
+}
+
+// Initialize implements gorm.Plugin.
+func (p Timestamps) Initialize(db *gorm.DB) error {
+	c := p.Clock
+	if c == nil {
+		c = timi.Now
+	}
+	db.Config.NowFunc = NowFunc(c)
+	return nil
+}
@@ -0,0 +1,63 @@
+package gorm
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ieshan/timi"
+	"gorm.io/gorm"
+	"gorm.io/gorm/utils/tests"
+)
+
+func TestTimestamps_Create(t *testing.T) {
+	db := newDryRunDB(t)
+	model := softDeleteModel{Name: "a"}
+	if err := db.Create(&model).Error; err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+	if model.CreatedAt != fixedTime || model.UpdatedAt.Time != fixedTime {
+		t.Fatalf("Expected %v, got %v and %v", fixedTime, model.CreatedAt, model.UpdatedAt)
+	}
+
+	// Times that are already set are kept
+	created := timi.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	model = softDeleteModel{Name: "b", CreatedAt: created}
+	if err := db.Create(&model).Error; err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+	if model.CreatedAt != created {
+		t.Fatalf("Expected %v, got %v", created, model.CreatedAt)
+	}
+}
+
+func TestTimestamps_Update(t *testing.T) {
+	db := newDryRunDB(t)
+	model := softDeleteModel{ID: 1, Name: "a", CreatedAt: timi.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)}
+	if err := db.Save(&model).Error; err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+	if model.UpdatedAt.Time != fixedTime {
+		t.Fatalf("Expected %v, got %v", fixedTime, model.UpdatedAt)
+	}
+}
+
+func TestTimestamps_DefaultClock(t *testing.T) {
+	db, err := gorm.Open(tests.DummyDialector{}, &gorm.Config{DryRun: true})
+	if err != nil {
+		t.Fatalf("Open failed: %v", err)
+	}
+	if err = db.Use(Timestamps{}); err != nil {
+		t.Fatalf("Use failed: %v", err)
+	}
+	before := timi.Now()
+	now := db.NowFunc()
+	if now.Location() != time.UTC || now.Before(before.Time) || now.After(time.Now()) {
+		t.Fatalf("Expected the current UTC time, got %v", now)
+	}
+
+	// A session can use another clock
+	session := db.Session(&gorm.Session{NowFunc: NowFunc(func() timi.Time { return fixedTime })})
+	if now = session.NowFunc(); !now.Equal(fixedTime.Time) {
+		t.Fatalf("Expected %v, got %v", fixedTime, now)
+	}
+}
@@ -0,0 +1,115 @@
+package gorm
+
+import (
+	"context"
+	"strconv"
+	"strings"
+
+	"github.com/ieshan/timi"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"gorm.io/gorm/schema"
+)
+
+// GORM Column Types for timi.Time
+// GORM derives the column type of timi.Time from sql.NullTime, which gives
+// datetime(3) on MySQL and loses microseconds. Time and UnixTime embed the
+// timi types, so they keep their methods and encodings, and choose the column
+// type of each dialect during auto-migration.
+//
+// The precision of Time columns is set with the timi tag option, such as
+// `gorm:"column:created_at;timi:precision=3"`, or with the precision tag of
+// GORM. It defaults to DefaultPrecision, microseconds.
+
+// DefaultPrecision is the number of fractional second digits of Time columns
+// without a precision option.
+const DefaultPrecision = 6
+
+// Time is a timi.Time with GORM column types:
+//
+//	postgres   timestamptz(p)
+//	mysql      datetime(p), for MySQL and MariaDB
+//	sqlserver  datetime2(p)
+//	sqlite     TEXT, written as sortable timi.SQLiteText
+//
+// Other dialects use the GORM time type.
+type Time struct{ timi.Time }
+
+// UnixTime is a timi.SQLiteUnix, Unix seconds, stored in an INTEGER column
+// on SQLite and a bigint column on other dialects.
+type UnixTime struct{ timi.SQLiteUnix }
+
+// GormDataType implements schema.GormDataTypeInterface.
+func (Time) GormDataType() string {
+	return string(schema.Time)
+}
+
+// GormDBDataType implements migrator.GormDataTypeInterface.
+func (Time) GormDBDataType(db *gorm.DB, field *schema.Field) string {
+	switch db.Dialector.Name() {
+	case "postgres":
+		return "timestamptz(" + strconv.Itoa(Precision(field)) + ")"
+	case "mysql":
+		return "datetime(" + strconv.Itoa(Precision(field)) + ")"
+	case "sqlserver":
+		return "datetime2(" + strconv.Itoa(Precision(field)) + ")"
+	case "sqlite":
+		return "TEXT"
+	}
+	return ""
+}
+
+// GormValue implements gorm.Valuer. On SQLite, t is written as
+// timi.SQLiteText, so that ORDER BY on the TEXT column is chronological.
+func (t Time) GormValue(ctx context.Context, db *gorm.DB) clause.Expr {
+	if db.Dialector.Name() == "sqlite" {
+		return clause.Expr{SQL: "?", Vars: []any{timi.SQLiteText{Time: t.Time}}}
+	}
+	return clause.Expr{SQL: "?", Vars: []any{t.Time}}
+}
+
+// Scan implements the sql.Scanner interface. Besides the values accepted by
+// timi.Time, it reads the TEXT, INTEGER and REAL representations of
+// timi.SQLiteText.
+func (t *Time) Scan(value interface{}) error {
+	if err := t.Time.Scan(value); err == nil {
+		return nil
+	}
+	var s timi.SQLiteText
+	if err := s.Scan(value); err != nil {
+		return err
+	}
+	t.Time = s.Time
+	return nil
+}
+
+// GormDataType implements schema.GormDataTypeInterface.
+func (UnixTime) GormDataType() string {
+	return string(schema.Int)
+}
+
+// GormDBDataType implements migrator.GormDataTypeInterface.
+func (UnixTime) GormDBDataType(db *gorm.DB, field *schema.Field) string {
+	if db.Dialector.Name() == "sqlite" {
+		return "INTEGER"
+	}
+	return "bigint"
+}
+
+// Precision returns the number of fractional second digits of a Time column,
+// from the timi:precision tag option, then the GORM precision tag, then
+// DefaultPrecision. It is limited to 0 to 6.
+func Precision(field *schema.Field) int {
+	p := DefaultPrecision
+	if field.Precision > 0 {
+		p = field.Precision
+	}
+	for _, opt := range strings.Split(field.TagSettings["TIMI"], ",") {
+		if key, value, ok := strings.Cut(strings.TrimSpace(opt), "="); ok && key == "precision" {
+			if v, err := strconv.Atoi(value); err == nil {
+				p = v
+			}
+		}
+	}
+	return min(max(p, 0), 6)
+}
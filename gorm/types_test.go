@@ -0,0 +1,151 @@
+package gorm
+
+import (
+	"context"
+	"database/sql/driver"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ieshan/timi"
+	"gorm.io/gorm"
+	"gorm.io/gorm/migrator"
+	"gorm.io/gorm/schema"
+)
+
+// dialector reports a dialect name; the tests only call Name.
+type dialector struct {
+	gorm.Dialector
+	name string
+}
+
+func (d dialector) Name() string {
+	return d.name
+}
+
+func newDB(name string) *gorm.DB {
+	return &gorm.DB{Config: &gorm.Config{Dialector: dialector{name: name}}}
+}
+
+type model struct {
+	ID        int64
+	CreatedAt Time
+	Millis    Time `gorm:"timi:precision=3"`
+	Seconds   Time `gorm:"precision:0;timi:precision=0"`
+	GormTag   Time `gorm:"precision:2"`
+	TooFine   Time `gorm:"timi:precision=9"`
+	Invalid   Time `gorm:"timi:precision=x"`
+	SeenAt    UnixTime
+}
+
+func parseModel(t *testing.T) *schema.Schema {
+	s, err := schema.Parse(&model{}, &sync.Map{}, schema.NamingStrategy{})
+	if err != nil {
+		t.Fatalf("Schema parse failed: %v", err)
+	}
+	return s
+}
+
+func TestGormDataType(t *testing.T) {
+	s := parseModel(t)
+	if dt := s.LookUpField("CreatedAt").DataType; dt != schema.Time {
+		t.Fatalf("Expected %v, got %v", schema.Time, dt)
+	}
+	if dt := s.LookUpField("SeenAt").DataType; dt != schema.Int {
+		t.Fatalf("Expected %v, got %v", schema.Int, dt)
+	}
+}
+
+func TestGormDBDataType(t *testing.T) {
+	s := parseModel(t)
+	testCases := []struct {
+		dialect  string
+		expected map[string]string
+	}{
+		{"postgres", map[string]string{
+			"CreatedAt": "timestamptz(6)", "Millis": "timestamptz(3)", "Seconds": "timestamptz(0)",
+			"GormTag": "timestamptz(2)", "TooFine": "timestamptz(6)", "Invalid": "timestamptz(6)", "SeenAt": "bigint",
+		}},
+		{"mysql", map[string]string{
+			"CreatedAt": "datetime(6)", "Millis": "datetime(3)", "Seconds": "datetime(0)",
+			"GormTag": "datetime(2)", "TooFine": "datetime(6)", "Invalid": "datetime(6)", "SeenAt": "bigint",
+		}},
+		{"sqlserver", map[string]string{"CreatedAt": "datetime2(6)", "Millis": "datetime2(3)", "SeenAt": "bigint"}},
+		{"sqlite", map[string]string{"CreatedAt": "TEXT", "Millis": "TEXT", "SeenAt": "INTEGER"}},
+		{"clickhouse", map[string]string{"CreatedAt": "", "SeenAt": "bigint"}},
+	}
+	for _, tc := range testCases {
+		db := newDB(tc.dialect)
+		for name, expected := range tc.expected {
+			field := s.LookUpField(name)
+			got := reflect.New(field.FieldType).Interface().(migrator.GormDataTypeInterface).GormDBDataType(db, field)
+			if got != expected {
+				t.Errorf("%s %s: expected %q, got %q", tc.dialect, name, expected, got)
+			}
+		}
+	}
+}
+
+func TestTime_GormValue(t *testing.T) {
+	ti := Time{timi.Date(2024, 1, 15, 10, 30, 45, 123456789, time.UTC)}
+	testCases := []struct {
+		dialect string
+		value   any
+	}{
+		{"sqlite", "2024-01-15 10:30:45.123456789"},
+		{"postgres", ti.Time.Time},
+		{"mysql", ti.Time.Time},
+	}
+	for _, tc := range testCases {
+		expr := ti.GormValue(context.Background(), newDB(tc.dialect))
+		if expr.SQL != "?" || len(expr.Vars) != 1 {
+			t.Fatalf("Unexpected expression %+v", expr)
+		}
+		value, err := expr.Vars[0].(driver.Valuer).Value()
+		if err != nil || value != tc.value {
+			t.Fatalf("%s: expected %v, got %v, %v", tc.dialect, tc.value, value, err)
+		}
+	}
+	value, err := Time{timi.NilTime}.GormValue(context.Background(), newDB("sqlite")).Vars[0].(driver.Valuer).Value()
+	if err != nil || value != nil {
+		t.Fatalf("Expected nil, got %v, %v", value, err)
+	}
+}
+
+func TestTime_Scan(t *testing.T) {
+	expected := timi.Date(2024, 1, 15, 10, 30, 45, 123456000, time.UTC)
+	for _, src := range []any{
+		expected.Time,
+		"2024-01-15 10:30:45.123456",
+		[]byte("2024-01-15 10:30:45.123456000"),
+		"2024-01-15 10:30:45.123456+00:00",
+	} {
+		var got Time
+		if err := got.Scan(src); err != nil {
+			t.Fatalf("Scan %v failed: %v", src, err)
+		}
+		if !got.Equal(expected) {
+			t.Fatalf("Expected %v, got %v", expected, got)
+		}
+	}
+	got := Time{timi.Now()}
+	if err := got.Scan(nil); err != nil || got.Time != timi.NilTime {
+		t.Fatalf("Expected %v, got %v, %v", timi.NilTime, got, err)
+	}
+	if err := got.Scan("tomorrow"); err == nil {
+		t.Fatalf("Expected an error for an unsupported string")
+	}
+}
+
+func TestUnixTime_Value(t *testing.T) {
+	ti := UnixTime{timi.SQLiteUnix{Time: timi.Date(2024, 1, 15, 10, 30, 45, 0, time.UTC)}}
+	value, err := ti.Value()
+	if err != nil || value != int64(1705314645) {
+		t.Fatalf("Expected 1705314645, got %v, %v", value, err)
+	}
+	var got UnixTime
+	if err = got.Scan(value); err != nil || !got.Equal(ti.Time) {
+		t.Fatalf("Expected %v, got %v, %v", ti, got, err)
+	}
+}
@@ -0,0 +1,158 @@
+package timi
+
+import (
+	"encoding/json"
+	"errors"
+	"math"
+	"time"
+)
+
+// Infinite times.
+//
+// Infinity and NegInfinity are valid times at the two ends of the range of
+// time.Time, so Before, After, Compare and Equal order them after and before
+// every finite time. They are encoded as "infinity" and "-infinity", the
+// values PostgreSQL uses for open-ended timestamp ranges, in SQL, text and
+// JSON. Add, AddDate, Truncate and Round leave them unchanged. The calendar
+// accessors, such as Year and Unix, are meaningless for them; check
+// IsFinite first.
+
+// unixToInternal is the number of seconds from January 1, year 1 to the Unix
+// epoch, the offset time.Unix adds to its seconds.
+const unixToInternal int64 = (1969*365 + 1969/4 - 1969/100 + 1969/400) * 24 * 60 * 60
+
+var (
+	// Infinity is a valid Time after every finite time.
+	Infinity = Time{Time: time.Unix(math.MaxInt64-unixToInternal, 999999999).UTC(), Valid: true}
+	// NegInfinity is a valid Time before every finite time.
+	NegInfinity = Time{Time: time.Unix(math.MinInt64, 0).UTC(), Valid: true}
+)
+
+const (
+	infinityText    = "infinity"
+	negInfinityText = "-infinity"
+)
+
+// The JSON values Infinity and NegInfinity are encoded as, set with
+// SetInfinityJSON.
+var (
+	infinityJSONValue    = `"infinity"`
+	negInfinityJSONValue = `"-infinity"`
+)
+
+// SetInfinityJSON sets the JSON values Infinity and NegInfinity are encoded
+// as, `"infinity"` and `"-infinity"` by default. They must be distinct valid
+// JSON other than null, such as a string or a number; set them to far-off
+// dates like `"9999-12-31T23:59:59Z"` for clients that only accept RFC 3339
+// times. When decoding, the configured values and the default strings are
+// accepted.
+//
+// SetInfinityJSON is not safe for concurrent use with encoding or decoding;
+// call it once during initialization, before any Time is marshalled.
+func SetInfinityJSON(infinity, negInfinity string) error {
+	for _, v := range []string{infinity, negInfinity} {
+		if !json.Valid([]byte(v)) || v == "null" {
+			return errors.New("timi: invalid JSON value for an infinite time: " + v)
+		}
+	}
+	if infinity == negInfinity {
+		return errors.New("timi: Infinity and NegInfinity need distinct JSON values")
+	}
+	infinityJSONValue, negInfinityJSONValue = infinity, negInfinity
+	return nil
+}
+
+// IsInfinity reports whether t is Infinity.
+func (t Time) IsInfinity() bool {
+	return t.Valid && t.Time.Equal(Infinity.Time)
+}
+
+// IsNegInfinity reports whether t is NegInfinity.
+func (t Time) IsNegInfinity() bool {
+	return t.Valid && t.Time.Equal(NegInfinity.Time)
+}
+
+// IsInfinite reports whether t is Infinity or NegInfinity.
+func (t Time) IsInfinite() bool {
+	return t.IsInfinity() || t.IsNegInfinity()
+}
+
+// IsFinite reports whether t is valid and neither Infinity nor NegInfinity.
+func (t Time) IsFinite() bool {
+	return t.Valid && !t.IsInfinite()
+}
+
+// infinityText returns the text of an infinite t, or "" for other times.
+func (t Time) infinityText() string {
+	switch {
+	case t.IsInfinity():
+		return infinityText
+	case t.IsNegInfinity():
+		return negInfinityText
+	}
+	return ""
+}
+
+// infinityJSON returns the JSON value of an infinite t, or "" for other
+// times.
+func (t Time) infinityJSON() string {
+	switch {
+	case t.IsInfinity():
+		return infinityJSONValue
+	case t.IsNegInfinity():
+		return negInfinityJSONValue
+	}
+	return ""
+}
+
+// parseInfinityText reports whether s is the text of an infinite time.
+func parseInfinityText[S string | []byte](s S) (Time, bool) {
+	switch string(s) {
+	case infinityText:
+		return Infinity, true
+	case negInfinityText:
+		return NegInfinity, true
+	}
+	return Time{}, false
+}
+
+// parseInfinityJSON reports whether the raw JSON value data is an infinite
+// time.
+func parseInfinityJSON(data []byte) (Time, bool) {
+	if string(data) == `"`+infinityText+`"` || string(data) == infinityJSONValue {
+		return Infinity, true
+	}
+	if string(data) == `"`+negInfinityText+`"` || string(data) == negInfinityJSONValue {
+		return NegInfinity, true
+	}
+	return Time{}, false
+}
+
+// parseInfinityToken is parseInfinityJSON for a JSON token of the given kind
+// ('"' or '0'), where s is the unquoted string or the number literal.
+func parseInfinityToken(kind byte, s string) (Time, bool) {
+	if kind == '"' {
+		if t, ok := parseInfinityText(s); ok {
+			return t, true
+		}
+		if isQuoted(infinityJSONValue, s) {
+			return Infinity, true
+		}
+		if isQuoted(negInfinityJSONValue, s) {
+			return NegInfinity, true
+		}
+		return Time{}, false
+	}
+	switch s {
+	case infinityJSONValue:
+		return Infinity, true
+	case negInfinityJSONValue:
+		return NegInfinity, true
+	}
+	return Time{}, false
+}
+
+// isQuoted reports whether the JSON string q is s in quotes.
+func isQuoted(q, s string) bool {
+	return len(q) == len(s)+2 && q[0] == '"' && q[len(q)-1] == '"' && q[1:len(q)-1] == s
+}
@@ -1,10 +1,15 @@
 module timi-integration-tests
 
-go 1.25
+go 1.25.0
 
 require (
 	github.com/ieshan/timi v0.0.0
+	github.com/ieshan/timi/gorm v0.0.0
+	github.com/ieshan/timi/mongodb v0.0.0
+	github.com/ieshan/timi/pgx v0.0.0
+	github.com/jackc/pgx/v5 v5.7.5
 	go.mongodb.org/mongo-driver/v2 v2.3.0
+	gopkg.in/yaml.v3 v3.0.1
 	gorm.io/driver/mysql v1.6.0
 	gorm.io/driver/postgres v1.6.0
 	gorm.io/driver/sqlite v1.6.0
@@ -17,7 +22,6 @@ require (
 	github.com/golang/snappy v1.0.0 // indirect
 	github.com/jackc/pgpassfile v1.0.0 // indirect
 	github.com/jackc/pgservicefile v0.0.0-20240606120523-5a60cdf6a761 // indirect
-	github.com/jackc/pgx/v5 v5.7.5 // indirect
 	github.com/jackc/puddle/v2 v2.2.2 // indirect
 	github.com/jinzhu/inflection v1.0.0 // indirect
 	github.com/jinzhu/now v1.1.5 // indirect
@@ -34,3 +38,9 @@ require (
 
 // Use local timi package
 replace github.com/ieshan/timi => ../
+
+replace github.com/ieshan/timi/gorm => ../gorm
+
+replace github.com/ieshan/timi/mongodb => ../mongodb
+
+replace github.com/ieshan/timi/pgx => ../pgx
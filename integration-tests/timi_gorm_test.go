@@ -7,6 +7,9 @@ import (
 	"time"
 
 	"github.com/ieshan/timi"
+	"github.com/ieshan/timi/bitemporal"
+	timigorm "github.com/ieshan/timi/gorm"
+	"github.com/ieshan/timi/sqlfilter"
 	"gorm.io/driver/mysql"
 	"gorm.io/driver/postgres"
 	"gorm.io/driver/sqlite"
@@ -34,6 +37,38 @@ type dbConfig struct {
 	createTableSQL string
 	setupFunc      func(*gorm.DB) error
 	cleanupFunc    func(*gorm.DB) error
+	// columnTypes are the expected types of the timi_migrate_test columns
+	columnTypes map[string]columnType
+}
+
+// columnType is a migrated column type and its fractional second precision,
+// where a precision of 0 is not checked.
+type columnType struct {
+	name      string
+	precision int64
+}
+
+type MigratedSqlStruct struct {
+	ID         int64             `gorm:"column:id;primaryKey"`
+	TimeField  timigorm.Time     `gorm:"column:time_field"`
+	MilliField timigorm.Time     `gorm:"column:milli_field;timi:precision=3"`
+	UnixField  timigorm.UnixTime `gorm:"column:unix_field"`
+}
+
+func (MigratedSqlStruct) TableName() string {
+	return "timi_migrate_test"
+}
+
+type SoftDeleteSqlStruct struct {
+	ID        int64              `gorm:"column:id;primaryKey"`
+	Name      string             `gorm:"column:name"`
+	CreatedAt timi.Time          `gorm:"column:created_at"`
+	UpdatedAt timigorm.Time      `gorm:"column:updated_at"`
+	DeletedAt timigorm.DeletedAt `gorm:"column:deleted_at"`
+}
+
+func (SoftDeleteSqlStruct) TableName() string {
+	return "timi_soft_delete_test"
 }
 
 func TestMySQL(t *testing.T) {
@@ -60,6 +95,11 @@ func TestMySQL(t *testing.T) {
 			}
 			return db.Exec("DROP DATABASE IF EXISTS `timi_test`;").Error
 		},
+		columnTypes: map[string]columnType{
+			"time_field":  {"datetime", 6},
+			"milli_field": {"datetime", 3},
+			"unix_field":  {"bigint", 0},
+		},
 	}
 
 	testDatabase(t, config, func() (*gorm.DB, error) {
@@ -69,6 +109,78 @@ func TestMySQL(t *testing.T) {
 	})
 }
 
+type zeroDateSqlStruct struct {
+	ID        int64          `gorm:"column:id;primaryKey"`
+	TimeField timi.MySQLTime `gorm:"column:time_field"`
+	NullTime  timi.MySQLTime `gorm:"column:null_time"`
+}
+
+func (zeroDateSqlStruct) TableName() string {
+	return "timi_zero_date_test"
+}
+
+// TestMariaDBZeroDates covers legacy schemas with NO_ZERO_DATE disabled,
+// which store 0000-00-00 00:00:00 in NOT NULL columns.
+func TestMariaDBZeroDates(t *testing.T) {
+	setupDB, err := gorm.Open(mysql.Open("root:password@tcp(mariadb:3306)/?charset=utf8mb4&parseTime=True&loc=UTC"), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("MariaDB connection error: %v", err)
+	}
+	if err = setupDB.Exec("CREATE DATABASE IF NOT EXISTS `timi_zero_date_test` COLLATE 'utf8mb4_unicode_ci';").Error; err != nil {
+		t.Fatalf("Database creation error: %v", err)
+	}
+	defer setupDB.Exec("DROP DATABASE IF EXISTS `timi_zero_date_test`;")
+
+	for _, parseTime := range []bool{true, false} {
+		t.Run(fmt.Sprintf("ParseTime=%v", parseTime), func(t *testing.T) {
+			// An empty sql_mode allows zero dates
+			dsn := fmt.Sprintf("root:password@tcp(mariadb:3306)/timi_zero_date_test?charset=utf8mb4&parseTime=%v&loc=UTC&sql_mode=%%27%%27", parseTime)
+			db, err := gorm.Open(mysql.Open(dsn), &gorm.Config{})
+			if err != nil {
+				t.Fatalf("MariaDB connection error: %v", err)
+			}
+			if err = db.Exec(`
+				CREATE TABLE timi_zero_date_test (
+					id BIGINT NOT NULL AUTO_INCREMENT,
+					time_field DATETIME NOT NULL DEFAULT '0000-00-00 00:00:00',
+					null_time DATETIME DEFAULT NULL,
+					PRIMARY KEY (id)
+				) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;`).Error; err != nil {
+				t.Fatalf("Table creation error: %v", err)
+			}
+			defer db.Exec("DROP TABLE IF EXISTS timi_zero_date_test;")
+
+			// Zero dates scan as NilTime, from text and, with parseTime,
+			// from the zero time.Time
+			if err = db.Exec("INSERT INTO timi_zero_date_test (null_time) VALUES ('0000-00-00 00:00:00')").Error; err != nil {
+				t.Fatalf("Insert error: %v", err)
+			}
+			var got zeroDateSqlStruct
+			if err = db.First(&got).Error; err != nil {
+				t.Fatalf("Query error: %v", err)
+			}
+			if got.TimeField.Time != timi.NilTime || got.NullTime.Time != timi.NilTime {
+				t.Errorf("Expected zero dates to scan as NilTime, got %v and %v", got.TimeField, got.NullTime)
+			}
+
+			// Other dates scan with and without parseTime, and times with
+			// Valid=false are written as NULL
+			ti := timi.Date(2024, time.January, 15, 10, 30, 45, 123456000, time.UTC)
+			row := zeroDateSqlStruct{TimeField: timi.MySQLTime{Time: ti}, NullTime: timi.MySQLTime{Time: timi.Time{Time: time.Now(), Valid: false}}}
+			if err = db.Create(&row).Error; err != nil {
+				t.Fatalf("Create error: %v", err)
+			}
+			got = zeroDateSqlStruct{}
+			if err = db.First(&got, row.ID).Error; err != nil {
+				t.Fatalf("Query error: %v", err)
+			}
+			if !got.TimeField.Time.Equal(ti.Truncate(time.Second)) || got.NullTime.Time != timi.NilTime {
+				t.Errorf("Expected %v and NULL, got %v and %v", ti.Truncate(time.Second), got.TimeField, got.NullTime)
+			}
+		})
+	}
+}
+
 func TestPostgres(t *testing.T) {
 	config := dbConfig{
 		name:       "PostgreSQL",
@@ -99,6 +211,11 @@ func TestPostgres(t *testing.T) {
 			}
 			return setupDB.Exec("DROP DATABASE IF EXISTS timi_test;").Error
 		},
+		columnTypes: map[string]columnType{
+			"time_field":  {"timestamptz", 6},
+			"milli_field": {"timestamptz", 3},
+			"unix_field":  {"int8", 0},
+		},
 	}
 
 	testDatabase(t, config, func() (*gorm.DB, error) {
@@ -124,6 +241,11 @@ func TestSQLite(t *testing.T) {
 			);`,
 		setupFunc:   func(db *gorm.DB) error { return nil }, // No setup needed for in-memory
 		cleanupFunc: func(db *gorm.DB) error { return nil }, // No cleanup needed for in-memory
+		columnTypes: map[string]columnType{
+			"time_field":  {"text", 0},
+			"milli_field": {"text", 0},
+			"unix_field":  {"integer", 0},
+		},
 	}
 
 	testDatabase(t, config, func() (*gorm.DB, error) {
@@ -133,6 +255,270 @@ func TestSQLite(t *testing.T) {
 	})
 }
 
+type sqliteModesSqlStruct struct {
+	ID     int64             `gorm:"column:id;primaryKey"`
+	Text   timi.SQLiteText   `gorm:"column:text_time"`
+	Unix   timi.SQLiteUnix   `gorm:"column:unix_time"`
+	Julian timi.SQLiteJulian `gorm:"column:julian_time"`
+}
+
+func (sqliteModesSqlStruct) TableName() string {
+	return "timi_sqlite_modes_test"
+}
+
+func TestSQLiteStorageModes(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("SQLite connection error: %v", err)
+	}
+	if err = db.Exec(`
+		CREATE TABLE timi_sqlite_modes_test (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			text_time TEXT,
+			unix_time INTEGER,
+			julian_time REAL
+		);`).Error; err != nil {
+		t.Fatalf("Table creation error: %v", err)
+	}
+
+	ti := timi.Date(2024, 1, 15, 10, 30, 45, 123456789, time.UTC)
+	row := sqliteModesSqlStruct{Text: timi.SQLiteText{Time: ti}, Unix: timi.SQLiteUnix{Time: ti}, Julian: timi.SQLiteJulian{Time: ti}}
+	if err = db.Create(&row).Error; err != nil {
+		t.Fatalf("Create error: %v", err)
+	}
+
+	t.Run("StorageClasses", func(t *testing.T) {
+		var types []string
+		if err := db.Raw("SELECT typeof(text_time) || ',' || typeof(unix_time) || ',' || typeof(julian_time) FROM timi_sqlite_modes_test WHERE id = ?", row.ID).Scan(&types).Error; err != nil {
+			t.Fatalf("Query error: %v", err)
+		}
+		if len(types) != 1 || types[0] != "text,integer,real" {
+			t.Errorf("Expected text,integer,real, got %v", types)
+		}
+	})
+
+	t.Run("DateFunctions", func(t *testing.T) {
+		// The stored values agree with the SQLite date functions
+		var match bool
+		if err := db.Raw(`SELECT CAST(strftime('%s', text_time) AS INTEGER) = unix_time
+			AND abs(julianday(text_time) - julian_time) < 1e-8
+			FROM timi_sqlite_modes_test WHERE id = ?`, row.ID).Scan(&match).Error; err != nil {
+			t.Fatalf("Query error: %v", err)
+		}
+		if !match {
+			t.Errorf("Expected the stored values to match the SQLite date functions")
+		}
+	})
+
+	t.Run("RoundTrip", func(t *testing.T) {
+		var got sqliteModesSqlStruct
+		if err := db.First(&got, row.ID).Error; err != nil {
+			t.Fatalf("Query error: %v", err)
+		}
+		if !got.Text.Equal(ti) || !got.Unix.Equal(ti.Truncate(time.Second)) || !got.Julian.Equal(ti.Truncate(time.Millisecond)) {
+			t.Errorf("Unexpected round trip %v, %v, %v", got.Text, got.Unix, got.Julian)
+		}
+	})
+
+	t.Run("AutoDetect", func(t *testing.T) {
+		// Rows written by other tools: text in the Unix and Julian columns,
+		// and a REAL in the text column, which reads it as a Julian day
+		// number as it is below 1e7
+		if err := db.Exec(`INSERT INTO timi_sqlite_modes_test (text_time, unix_time, julian_time) VALUES
+			(julianday('2024-01-15 10:30:45'), datetime('2024-01-15 10:30:45'), datetime('2024-01-15 10:30:45')),
+			(NULL, NULL, NULL)`).Error; err != nil {
+			t.Fatalf("Insert error: %v", err)
+		}
+		var got []sqliteModesSqlStruct
+		if err := db.Where("id > ?", row.ID).Order("id").Find(&got).Error; err != nil {
+			t.Fatalf("Query error: %v", err)
+		}
+		if len(got) != 2 {
+			t.Fatalf("Expected 2 rows, got %d", len(got))
+		}
+		expected := timi.Date(2024, 1, 15, 10, 30, 45, 0, time.UTC)
+		if !got[0].Text.Equal(expected) || !got[0].Unix.Equal(expected) || !got[0].Julian.Equal(expected) {
+			t.Errorf("Expected %v, got %v, %v, %v", expected, got[0].Text, got[0].Unix, got[0].Julian)
+		}
+		if !got[1].Text.IsNull() || !got[1].Unix.IsNull() || !got[1].Julian.IsNull() {
+			t.Errorf("Expected nulls, got %+v", got[1])
+		}
+	})
+
+	t.Run("SortableText", func(t *testing.T) {
+		if err := db.Exec("DELETE FROM timi_sqlite_modes_test").Error; err != nil {
+			t.Fatalf("Delete error: %v", err)
+		}
+		times := []timi.Time{
+			timi.Date(2024, 1, 15, 10, 30, 45, 500000000, time.UTC),
+			timi.Date(2024, 1, 15, 10, 30, 45, 0, time.UTC),
+			timi.Date(2024, 1, 15, 10, 30, 46, 0, time.UTC),
+			timi.Date(2024, 1, 15, 10, 30, 45, 1, time.UTC),
+			timi.Date(999, 1, 1, 0, 0, 0, 0, time.UTC),
+		}
+		for _, ti := range times {
+			if err := db.Create(&sqliteModesSqlStruct{Text: timi.SQLiteText{Time: ti}}).Error; err != nil {
+				t.Fatalf("Create error: %v", err)
+			}
+		}
+		var got []sqliteModesSqlStruct
+		if err := db.Order("text_time").Find(&got).Error; err != nil {
+			t.Fatalf("Query error: %v", err)
+		}
+		for i := 1; i < len(got); i++ {
+			if !got[i-1].Text.Before(got[i].Text.Time) {
+				t.Errorf("Expected %v before %v", got[i-1].Text, got[i].Text)
+			}
+		}
+	})
+}
+
+// kathmanduZone is the storage zone of naive_local, with a quarter-hour
+// offset.
+type kathmanduZone struct{}
+
+var kathmandu, _ = time.LoadLocation("Asia/Kathmandu")
+
+func (kathmanduZone) Location() *time.Location {
+	return kathmandu
+}
+
+type storageZoneSqlStruct struct {
+	ID    int64                     `gorm:"column:id;primaryKey"`
+	UTC   timi.NaiveUTC             `gorm:"column:naive_utc"`
+	Local timi.Naive[kathmanduZone] `gorm:"column:naive_local"`
+}
+
+func (storageZoneSqlStruct) TableName() string {
+	return "timi_storage_zone_test"
+}
+
+// The session zones below are Pacific/Chatham, +13:45 in January, so that a
+// value converted to or from the session zone is off by a visible amount.
+
+func TestMySQLStorageZone(t *testing.T) {
+	setupDB, err := gorm.Open(mysql.Open("root:password@tcp(mariadb:3306)/?charset=utf8mb4&parseTime=True&loc=UTC"), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("MariaDB connection error: %v", err)
+	}
+	if err = setupDB.Exec("CREATE DATABASE IF NOT EXISTS `timi_storage_zone_test` COLLATE 'utf8mb4_unicode_ci';").Error; err != nil {
+		t.Fatalf("Database creation error: %v", err)
+	}
+	defer setupDB.Exec("DROP DATABASE IF EXISTS `timi_storage_zone_test`;")
+
+	// The driver parses and formats DATETIME in loc, and the server converts
+	// TIMESTAMP and NOW() with time_zone
+	db, err := gorm.Open(mysql.Open("root:password@tcp(mariadb:3306)/timi_storage_zone_test?charset=utf8mb4&parseTime=True&loc=Pacific%2FChatham&time_zone=%27%2B13%3A45%27"), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("MariaDB connection error: %v", err)
+	}
+	testStorageZone(t, db, "MySQL", `
+		CREATE TABLE timi_storage_zone_test (
+			id BIGINT NOT NULL AUTO_INCREMENT,
+			naive_utc DATETIME(6) DEFAULT NULL,
+			naive_local DATETIME(6) DEFAULT NULL,
+			PRIMARY KEY (id)
+		) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;`, "CAST(%s AS CHAR)")
+}
+
+func TestPostgresStorageZone(t *testing.T) {
+	db, err := gorm.Open(postgres.Open("host=postgres user=postgres password=password port=5432 sslmode=disable TimeZone=Pacific/Chatham"), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("PostgreSQL connection error: %v", err)
+	}
+	testStorageZone(t, db, "PostgreSQL", `
+		CREATE TABLE timi_storage_zone_test (
+			id BIGSERIAL PRIMARY KEY,
+			naive_utc TIMESTAMP DEFAULT NULL,
+			naive_local TIMESTAMP DEFAULT NULL
+		);`, "%s::text")
+}
+
+func TestSQLiteStorageZone(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open("file::memory:?_loc=Pacific%2FChatham"), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("SQLite connection error: %v", err)
+	}
+	testStorageZone(t, db, "SQLite", `
+		CREATE TABLE timi_storage_zone_test (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			naive_utc TEXT,
+			naive_local TEXT
+		);`, "%s")
+}
+
+// testStorageZone checks that Naive columns hold the wall clock of their
+// storage zone, whatever the zone of the connection. text is the expression
+// that reads a column as text.
+func testStorageZone(t *testing.T, db *gorm.DB, dbName, createTableSQL, text string) {
+	if err := db.Exec(createTableSQL).Error; err != nil {
+		t.Fatalf("%s: Table creation error: %v", dbName, err)
+	}
+	defer db.Exec("DROP TABLE IF EXISTS timi_storage_zone_test;")
+
+	ti := timi.Date(2024, 1, 15, 10, 30, 45, 123456000, time.UTC)
+	row := storageZoneSqlStruct{UTC: timi.NaiveUTC{Time: ti}, Local: timi.Naive[kathmanduZone]{Time: ti}}
+	if err := db.Create(&row).Error; err != nil {
+		t.Fatalf("%s: Create error: %v", dbName, err)
+	}
+
+	t.Run("StoredWallClock", func(t *testing.T) {
+		var stored struct{ UTC, Local string }
+		query := fmt.Sprintf("SELECT %s AS utc, %s AS local FROM timi_storage_zone_test WHERE id = ?",
+			fmt.Sprintf(text, "naive_utc"), fmt.Sprintf(text, "naive_local"))
+		if err := db.Raw(query, row.ID).Scan(&stored).Error; err != nil {
+			t.Fatalf("%s: Query error: %v", dbName, err)
+		}
+		if stored.UTC != "2024-01-15 10:30:45.123456" {
+			t.Errorf("%s: Expected the UTC wall clock, got %q", dbName, stored.UTC)
+		}
+		if stored.Local != "2024-01-15 16:15:45.123456" {
+			t.Errorf("%s: Expected the Asia/Kathmandu wall clock, got %q", dbName, stored.Local)
+		}
+	})
+
+	t.Run("RoundTrip", func(t *testing.T) {
+		var got storageZoneSqlStruct
+		if err := db.First(&got, row.ID).Error; err != nil {
+			t.Fatalf("%s: Query error: %v", dbName, err)
+		}
+		if got.UTC.Time != ti || got.Local.Time != ti {
+			t.Errorf("%s: Expected %v, got %v and %v", dbName, ti, got.UTC.Time, got.Local.Time)
+		}
+	})
+
+	t.Run("WrittenByOtherTools", func(t *testing.T) {
+		// Literals are stored as they are, so they read back in the storage zone
+		if err := db.Exec("INSERT INTO timi_storage_zone_test (naive_utc, naive_local) VALUES ('2024-01-15 10:30:45.123456', '2024-01-15 16:15:45.123456'), (NULL, NULL)").Error; err != nil {
+			t.Fatalf("%s: Insert error: %v", dbName, err)
+		}
+		var rows []storageZoneSqlStruct
+		if err := db.Where("id > ?", row.ID).Order("id").Find(&rows).Error; err != nil {
+			t.Fatalf("%s: Query error: %v", dbName, err)
+		}
+		if len(rows) != 2 {
+			t.Fatalf("%s: Expected 2 rows, got %d", dbName, len(rows))
+		}
+		if rows[0].UTC.Time != ti || rows[0].Local.Time != ti {
+			t.Errorf("%s: Expected %v, got %v and %v", dbName, ti, rows[0].UTC.Time, rows[0].Local.Time)
+		}
+		if rows[1].UTC.Time != timi.NilTime || rows[1].Local.Time != timi.NilTime {
+			t.Errorf("%s: Expected NULL to scan as NilTime, got %v and %v", dbName, rows[1].UTC.Time, rows[1].Local.Time)
+		}
+	})
+
+	t.Run("Filter", func(t *testing.T) {
+		// A bound compared against a naive column is written the same way
+		var count int64
+		if err := db.Model(&storageZoneSqlStruct{}).Where("naive_local = ?", timi.Naive[kathmanduZone]{Time: ti}).Count(&count).Error; err != nil {
+			t.Fatalf("%s: Count error: %v", dbName, err)
+		}
+		if count != 2 {
+			t.Errorf("%s: Expected 2 rows, got %d", dbName, count)
+		}
+	})
+}
+
 func testDatabase(t *testing.T, config dbConfig, setupDBFunc, connectDBFunc func() (*gorm.DB, error)) {
 	// Setup database if needed
 	if config.setupFunc != nil {
@@ -204,6 +590,34 @@ func testDatabase(t *testing.T, config dbConfig, setupDBFunc, connectDBFunc func
 	t.Run("ComponentMethods", func(t *testing.T) {
 		testSQLComponentMethods(t, db, config.name)
 	})
+
+	t.Run("MigratedColumnTypes", func(t *testing.T) {
+		testSQLMigratedColumnTypes(t, db, config.name, config.columnTypes)
+	})
+
+	t.Run("SoftDeleteAndTimestamps", func(t *testing.T) {
+		testSQLSoftDeleteAndTimestamps(t, db, config.name)
+	})
+
+	t.Run("SQLFilters", func(t *testing.T) {
+		testSQLFilters(t, db, config.name)
+	})
+
+	t.Run("Slices", func(t *testing.T) {
+		testSQLSlices(t, db, config.name)
+	})
+
+	t.Run("Bitemporal", func(t *testing.T) {
+		testSQLBitemporal(t, db, config.name)
+	})
+
+	t.Run("BitemporalSuperseding", func(t *testing.T) {
+		testSQLBitemporalSuperseding(t, db, config.name)
+	})
+
+	t.Run("PartialUpdate", func(t *testing.T) {
+		testSQLPartialUpdate(t, db, config.name)
+	})
 }
 
 func testSQLBasicRoundTrip(t *testing.T, db *gorm.DB, dbName string) {
@@ -715,3 +1129,503 @@ func timesApproximatelyEqual(t1, t2 timi.Time) bool {
 	}
 	return diff <= time.Millisecond
 }
+
+func testSQLMigratedColumnTypes(t *testing.T, db *gorm.DB, dbName string, expected map[string]columnType) {
+	if err := db.AutoMigrate(&MigratedSqlStruct{}); err != nil {
+		t.Fatalf("%s: AutoMigrate failed: %v", dbName, err)
+	}
+	defer db.Migrator().DropTable(&MigratedSqlStruct{})
+
+	columnTypes, err := db.Migrator().ColumnTypes(&MigratedSqlStruct{})
+	if err != nil {
+		t.Fatalf("%s: Failed to read column types: %v", dbName, err)
+	}
+	found := 0
+	for _, ct := range columnTypes {
+		want, ok := expected[ct.Name()]
+		if !ok {
+			continue
+		}
+		found++
+		if !strings.EqualFold(ct.DatabaseTypeName(), want.name) {
+			t.Errorf("%s: %s: expected type %s, got %s", dbName, ct.Name(), want.name, ct.DatabaseTypeName())
+		}
+		if want.precision > 0 {
+			if precision, _, ok := ct.DecimalSize(); !ok || precision != want.precision {
+				t.Errorf("%s: %s: expected precision %d, got %d", dbName, ct.Name(), want.precision, precision)
+			}
+		}
+	}
+	if found != len(expected) {
+		t.Fatalf("%s: expected %d migrated columns, found %d", dbName, len(expected), found)
+	}
+
+	// Values round-trip at the precision of each column
+	testTime := timi.Date(2024, time.March, 15, 14, 30, 45, 123456789, time.UTC)
+	record := MigratedSqlStruct{
+		TimeField:  timigorm.Time{Time: testTime},
+		MilliField: timigorm.Time{Time: testTime},
+		UnixField:  timigorm.UnixTime{SQLiteUnix: timi.SQLiteUnix{Time: testTime}},
+	}
+	if err = db.Create(&record).Error; err != nil {
+		t.Fatalf("%s: Failed to insert migrated record: %v", dbName, err)
+	}
+	var retrieved MigratedSqlStruct
+	if err = db.First(&retrieved, record.ID).Error; err != nil {
+		t.Fatalf("%s: Failed to retrieve migrated record: %v", dbName, err)
+	}
+	if diff := retrieved.TimeField.Sub(testTime).Abs(); diff >= time.Microsecond {
+		t.Errorf("%s: time_field: expected %v to microseconds, got %v", dbName, testTime, retrieved.TimeField)
+	}
+	if diff := retrieved.MilliField.Sub(testTime).Abs(); dbName != "SQLite" && diff >= time.Millisecond {
+		t.Errorf("%s: milli_field: expected %v to milliseconds, got %v", dbName, testTime, retrieved.MilliField)
+	}
+	if dbName == "SQLite" {
+		// Time is written as sortable text on SQLite
+		var text string
+		if err = db.Raw("SELECT time_field FROM timi_migrate_test WHERE id = ?", record.ID).Scan(&text).Error; err != nil {
+			t.Fatalf("%s: Failed to read time_field: %v", dbName, err)
+		}
+		if text != "2024-03-15 14:30:45.123456789" {
+			t.Errorf("%s: time_field: expected sortable text, got %s", dbName, text)
+		}
+	}
+	if !retrieved.UnixField.Equal(testTime.Truncate(time.Second)) {
+		t.Errorf("%s: unix_field: expected %v, got %v", dbName, testTime.Truncate(time.Second), retrieved.UnixField)
+	}
+}
+
+func testSQLSoftDeleteAndTimestamps(t *testing.T, db *gorm.DB, dbName string) {
+	if err := db.AutoMigrate(&SoftDeleteSqlStruct{}); err != nil {
+		t.Fatalf("%s: AutoMigrate failed: %v", dbName, err)
+	}
+	defer db.Migrator().DropTable(&SoftDeleteSqlStruct{})
+
+	now := timi.Date(2024, time.March, 15, 14, 30, 45, 0, time.UTC)
+	clock := func() timi.Time { return now }
+	db = db.Session(&gorm.Session{NowFunc: timigorm.NowFunc(clock)})
+
+	// Auto timestamps read the clock
+	records := []SoftDeleteSqlStruct{{Name: "kept"}, {Name: "deleted"}}
+	if err := db.Create(&records).Error; err != nil {
+		t.Fatalf("%s: Failed to insert records: %v", dbName, err)
+	}
+	var retrieved SoftDeleteSqlStruct
+	if err := db.First(&retrieved, records[0].ID).Error; err != nil {
+		t.Fatalf("%s: Failed to retrieve record: %v", dbName, err)
+	}
+	if !retrieved.CreatedAt.Equal(now) || !retrieved.UpdatedAt.Equal(now) || !retrieved.DeletedAt.IsNull() {
+		t.Errorf("%s: expected created and updated at %v, got %+v", dbName, now, retrieved)
+	}
+
+	now = now.Add(time.Hour)
+	if err := db.Model(&retrieved).Update("name", "updated").Error; err != nil {
+		t.Fatalf("%s: Failed to update record: %v", dbName, err)
+	}
+	if err := db.First(&retrieved, records[0].ID).Error; err != nil {
+		t.Fatalf("%s: Failed to retrieve record: %v", dbName, err)
+	}
+	if !retrieved.UpdatedAt.Equal(now) || !retrieved.CreatedAt.Equal(now.Add(-time.Hour)) {
+		t.Errorf("%s: expected updated at %v, got %+v", dbName, now, retrieved)
+	}
+
+	// Delete sets deleted_at, and scoped queries exclude the row
+	now = now.Add(time.Hour)
+	if err := db.Delete(&records[1]).Error; err != nil {
+		t.Fatalf("%s: Failed to delete record: %v", dbName, err)
+	}
+	if !records[1].DeletedAt.Equal(now) {
+		t.Errorf("%s: expected deleted at %v, got %v", dbName, now, records[1].DeletedAt)
+	}
+	var count int64
+	if err := db.Model(&SoftDeleteSqlStruct{}).Count(&count).Error; err != nil || count != 1 {
+		t.Errorf("%s: expected 1 scoped record, got %d, %v", dbName, count, err)
+	}
+	var found []SoftDeleteSqlStruct
+	if err := db.Find(&found, records[1].ID).Error; err != nil || len(found) != 0 {
+		t.Errorf("%s: expected the deleted record to be excluded, got %v, %v", dbName, found, err)
+	}
+	var deleted SoftDeleteSqlStruct
+	if err := db.Unscoped().First(&deleted, records[1].ID).Error; err != nil {
+		t.Fatalf("%s: Failed to retrieve deleted record: %v", dbName, err)
+	}
+	if !deleted.DeletedAt.Equal(now) {
+		t.Errorf("%s: expected deleted at %v, got %v", dbName, now, deleted.DeletedAt)
+	}
+
+	// Unscoped deletes remove the row
+	if err := db.Unscoped().Delete(&records[1]).Error; err != nil {
+		t.Fatalf("%s: Failed to delete record: %v", dbName, err)
+	}
+	if err := db.Unscoped().Model(&SoftDeleteSqlStruct{}).Count(&count).Error; err != nil || count != 1 {
+		t.Errorf("%s: expected 1 record, got %d, %v", dbName, count, err)
+	}
+}
+
+func testSQLFilters(t *testing.T, db *gorm.DB, dbName string) {
+	// Clear table
+	db.Exec("DELETE FROM timi_test")
+
+	// Periods [time_field, null_time), where a null end is ongoing
+	day := func(d int) timi.Time { return timi.Date(2024, time.January, d, 0, 0, 0, 0, time.UTC) }
+	records := []TimeTestSqlStruct{
+		{Name: "january_1_to_5", TimeField: day(1), NullTime: day(5)},
+		{Name: "january_5_to_10", TimeField: day(5), NullTime: day(10)},
+		{Name: "january_10_ongoing", TimeField: day(10), NullTime: timi.NilTime},
+	}
+	if err := db.Create(&records).Error; err != nil {
+		t.Fatalf("%s: Failed to insert filter records: %v", dbName, err)
+	}
+
+	testCases := []struct {
+		name     string
+		p        sqlfilter.Predicate
+		expected []string
+	}{
+		{"IsNull", sqlfilter.IsNull("null_time"), []string{"january_10_ongoing"}},
+		{"HalfOpen", sqlfilter.Between("time_field", day(1), day(10), sqlfilter.ClosedOpen), []string{"january_1_to_5", "january_5_to_10"}},
+		{"UnboundedUpper", sqlfilter.Between("time_field", day(5), timi.NilTime, sqlfilter.ClosedOpen), []string{"january_5_to_10", "january_10_ongoing"}},
+		{"WindowOrNull", sqlfilter.Or(sqlfilter.Between("time_field", day(1), day(5), sqlfilter.ClosedOpen), sqlfilter.IsNull("null_time")), []string{"january_1_to_5", "january_10_ongoing"}},
+		{"Overlaps", sqlfilter.Overlaps("time_field", "null_time", day(5), day(6)), []string{"january_5_to_10"}},
+		{"OverlapsOngoing", sqlfilter.Overlaps("time_field", "null_time", day(20), timi.NilTime), []string{"january_10_ongoing"}},
+		{"OverlapsEverything", sqlfilter.Overlaps("time_field", "null_time", timi.NilTime, timi.NilTime), []string{"january_1_to_5", "january_5_to_10", "january_10_ongoing"}},
+	}
+
+	sqlDB, err := db.DB()
+	if err != nil {
+		t.Fatalf("%s: DB connection error: %v", dbName, err)
+	}
+	ph := sqlfilter.PlaceholderOf(db.Dialector.Name())
+	for _, tc := range testCases {
+		// database/sql, with the placeholders of the dialect after the name argument
+		query, args := tc.p.Build(ph, 2)
+		first := "?"
+		if ph == sqlfilter.Dollar {
+			first = "$1"
+		}
+		rows, err := sqlDB.Query("SELECT name FROM timi_test WHERE name <> "+first+" AND ("+query+") ORDER BY time_field", append([]any{""}, args...)...)
+		if err != nil {
+			t.Fatalf("%s: %s: query error: %v", dbName, tc.name, err)
+		}
+		var names []string
+		for rows.Next() {
+			var name string
+			if err = rows.Scan(&name); err != nil {
+				t.Fatalf("%s: %s: scan error: %v", dbName, tc.name, err)
+			}
+			names = append(names, name)
+		}
+		rows.Close()
+		if fmt.Sprint(names) != fmt.Sprint(tc.expected) {
+			t.Errorf("%s: %s: database/sql: expected %v, got %v", dbName, tc.name, tc.expected, names)
+		}
+
+		// GORM Where
+		var found []TimeTestSqlStruct
+		if err = db.Where(tc.p.String(), tc.p.Args()...).Order("time_field").Find(&found).Error; err != nil {
+			t.Fatalf("%s: %s: GORM query error: %v", dbName, tc.name, err)
+		}
+		names = names[:0]
+		for _, r := range found {
+			names = append(names, r.Name)
+		}
+		if fmt.Sprint(names) != fmt.Sprint(tc.expected) {
+			t.Errorf("%s: %s: GORM: expected %v, got %v", dbName, tc.name, tc.expected, names)
+		}
+	}
+}
+
+func testSQLSlices(t *testing.T, db *gorm.DB, dbName string) {
+	// PostgreSQL stores timi.Slice in a timestamptz[] column; every database
+	// stores timi.JSONSlice as a JSON array
+	var createSQL string
+	switch db.Dialector.Name() {
+	case "postgres":
+		createSQL = "CREATE TABLE timi_slice_test (id BIGSERIAL PRIMARY KEY, history TIMESTAMPTZ[], history_json JSONB)"
+	case "mysql":
+		createSQL = "CREATE TABLE timi_slice_test (id BIGINT NOT NULL AUTO_INCREMENT PRIMARY KEY, history_json JSON)"
+	default:
+		createSQL = "CREATE TABLE timi_slice_test (id INTEGER PRIMARY KEY AUTOINCREMENT, history_json TEXT)"
+	}
+	if err := db.Exec(createSQL).Error; err != nil {
+		t.Fatalf("%s: Failed to create slice table: %v", dbName, err)
+	}
+	defer db.Exec("DROP TABLE timi_slice_test")
+
+	history := []timi.Time{
+		timi.Date(2024, time.January, 15, 10, 30, 45, 123456000, time.UTC),
+		timi.NilTime,
+		timi.Infinity,
+		timi.Date(2024, time.February, 1, 0, 0, 0, 0, time.UTC),
+	}
+	assertHistory := func(kind string, got []timi.Time) {
+		if len(got) != len(history) {
+			t.Fatalf("%s: %s: expected %v, got %v", dbName, kind, history, got)
+		}
+		for i := range history {
+			if got[i].Valid != history[i].Valid || !got[i].Equal(history[i]) {
+				t.Errorf("%s: %s: element %d: expected %v, got %v", dbName, kind, i, history[i], got[i])
+			}
+		}
+	}
+
+	if db.Dialector.Name() == "postgres" {
+		if err := db.Exec("INSERT INTO timi_slice_test (history, history_json) VALUES (?, ?), (NULL, NULL)",
+			timi.Slice(history), timi.JSONSlice(history)).Error; err != nil {
+			t.Fatalf("%s: Failed to insert slices: %v", dbName, err)
+		}
+		var got timi.Slice
+		if err := db.Raw("SELECT history FROM timi_slice_test ORDER BY id LIMIT 1").Row().Scan(&got); err != nil {
+			t.Fatalf("%s: Failed to scan timestamptz[]: %v", dbName, err)
+		}
+		assertHistory("timestamptz[]", got)
+		var length int
+		if err := db.Raw("SELECT array_length(history, 1) FROM timi_slice_test ORDER BY id LIMIT 1").Row().Scan(&length); err != nil || length != len(history) {
+			t.Errorf("%s: expected a native array of %d elements, got %d, %v", dbName, len(history), length, err)
+		}
+	} else {
+		if err := db.Exec("INSERT INTO timi_slice_test (history_json) VALUES (?), (NULL)", timi.JSONSlice(history)).Error; err != nil {
+			t.Fatalf("%s: Failed to insert slices: %v", dbName, err)
+		}
+	}
+
+	var got timi.JSONSlice
+	if err := db.Raw("SELECT history_json FROM timi_slice_test ORDER BY id LIMIT 1").Row().Scan(&got); err != nil {
+		t.Fatalf("%s: Failed to scan JSON array: %v", dbName, err)
+	}
+	assertHistory("JSON", got)
+	var length int
+	lengthSQL := "SELECT json_array_length(history_json) FROM timi_slice_test ORDER BY id LIMIT 1"
+	switch db.Dialector.Name() {
+	case "postgres":
+		lengthSQL = "SELECT jsonb_array_length(history_json) FROM timi_slice_test ORDER BY id LIMIT 1"
+	case "mysql":
+		lengthSQL = "SELECT JSON_LENGTH(history_json) FROM timi_slice_test ORDER BY id LIMIT 1"
+	}
+	if err := db.Raw(lengthSQL).Row().Scan(&length); err != nil || length != len(history) {
+		t.Errorf("%s: expected a JSON array of %d elements, got %d, %v", dbName, len(history), length, err)
+	}
+
+	// NULL columns scan as nil slices
+	got = timi.JSONSlice{timi.Now()}
+	if err := db.Raw("SELECT history_json FROM timi_slice_test ORDER BY id DESC LIMIT 1").Row().Scan(&got); err != nil || got != nil {
+		t.Errorf("%s: expected a nil slice, got %v, %v", dbName, got, err)
+	}
+}
+
+func testSQLBitemporal(t *testing.T, db *gorm.DB, dbName string) {
+	// Clear table
+	db.Exec("DELETE FROM timi_test")
+
+	// Versions of one price, valid over [time_field, null_time) and recorded
+	// at created_at
+	columns := bitemporal.Columns{ValidFrom: "time_field", ValidTo: "null_time", RecordedAt: "created_at"}
+	day := func(d int) timi.Time { return timi.Date(2024, time.January, d, 0, 0, 0, 0, time.UTC) }
+
+	// load reads the stored versions back, as an application would before
+	// recording a new one
+	load := func() []bitemporal.Version[TimeTestSqlStruct] {
+		var rows []TimeTestSqlStruct
+		if err := db.Order("time_field").Find(&rows).Error; err != nil {
+			t.Fatalf("%s: Query error: %v", dbName, err)
+		}
+		history := make([]bitemporal.Version[TimeTestSqlStruct], len(rows))
+		for i, r := range rows {
+			history[i] = bitemporal.Version[TimeTestSqlStruct]{Period: bitemporal.Period{From: r.TimeField, To: r.NullTime}, RecordedAt: r.CreatedAt, Value: r}
+		}
+		return history
+	}
+	record := func(name string, period bitemporal.Period, recordedAt timi.Time) {
+		changes, err := bitemporal.Insert(load(), bitemporal.Version[TimeTestSqlStruct]{
+			Period:     period,
+			RecordedAt: recordedAt,
+			Value:      TimeTestSqlStruct{Name: name},
+		})
+		if err != nil {
+			t.Fatalf("%s: Insert %s failed: %v", dbName, name, err)
+		}
+		for _, v := range changes.Updated {
+			if err = db.Model(&v.Value).Updates(map[string]any{"time_field": v.From, "null_time": v.To}).Error; err != nil {
+				t.Fatalf("%s: Update error: %v", dbName, err)
+			}
+		}
+		for _, v := range changes.Deleted {
+			if err = db.Delete(&v.Value).Error; err != nil {
+				t.Fatalf("%s: Delete error: %v", dbName, err)
+			}
+		}
+		for _, v := range changes.Inserted {
+			v.Value.ID = 0
+			v.Value.TimeField, v.Value.NullTime, v.Value.CreatedAt = v.From, v.To, v.RecordedAt
+			if err = db.Create(&v.Value).Error; err != nil {
+				t.Fatalf("%s: Create error: %v", dbName, err)
+			}
+		}
+	}
+	record("price_1", bitemporal.Period{From: day(1)}, day(1))
+	record("price_2", bitemporal.Period{From: day(10)}, day(10))
+	// A correction recorded later splits price_1
+	record("price_fix", bitemporal.Period{From: day(5), To: day(8)}, day(20))
+
+	testCases := []struct {
+		name     string
+		p        sqlfilter.Predicate
+		expected []string
+	}{
+		{"Current", columns.Current(), []string{"price_2"}},
+		{"AsOfNow", columns.AsOf(day(6), timi.NilTime), []string{"price_fix"}},
+		{"AsOfSplit", columns.AsOf(day(9), timi.NilTime), []string{"price_1"}},
+		// Insert updated price_1 in place, so what was known before the
+		// correction is lost
+		{"KnownBeforeCorrection", columns.AsOf(day(6), day(15)), nil},
+		{"KnownAt", columns.AsOf(day(12), day(15)), []string{"price_2"}},
+		{"KnownBeforeRecorded", columns.AsOf(day(12), day(5)), nil},
+		{"Overlapping", columns.Overlapping(bitemporal.Period{From: day(4), To: day(9)}), []string{"price_1", "price_fix", "price_1"}},
+	}
+	for _, tc := range testCases {
+		var found []TimeTestSqlStruct
+		if err := db.Where(tc.p.String(), tc.p.Args()...).Order("time_field").Find(&found).Error; err != nil {
+			t.Fatalf("%s: %s: query error: %v", dbName, tc.name, err)
+		}
+		var names []string
+		for _, r := range found {
+			names = append(names, r.Name)
+		}
+		if fmt.Sprint(names) != fmt.Sprint(tc.expected) {
+			t.Errorf("%s: %s: expected %v, got %v", dbName, tc.name, tc.expected, names)
+		}
+	}
+
+	// The stored periods do not overlap
+	if history := load(); len(history) != 4 {
+		t.Errorf("%s: Expected 4 versions, got %d", dbName, len(history))
+	} else if err := bitemporal.Validate(history); err != nil {
+		t.Errorf("%s: Expected valid versions, got %v", dbName, err)
+	}
+}
+
+func testSQLBitemporalSuperseding(t *testing.T, db *gorm.DB, dbName string) {
+	// Clear table
+	db.Exec("DELETE FROM timi_test")
+
+	// As testSQLBitemporal, with versions superseded at updated_at instead
+	// of updated in place
+	columns := bitemporal.Columns{ValidFrom: "time_field", ValidTo: "null_time", RecordedAt: "created_at", SupersededAt: "updated_at"}
+	day := func(d int) timi.Time { return timi.Date(2024, time.January, d, 0, 0, 0, 0, time.UTC) }
+
+	load := func() []bitemporal.Version[TimeTestSqlStruct] {
+		var rows []TimeTestSqlStruct
+		if err := db.Order("time_field").Find(&rows).Error; err != nil {
+			t.Fatalf("%s: Query error: %v", dbName, err)
+		}
+		history := make([]bitemporal.Version[TimeTestSqlStruct], len(rows))
+		for i, r := range rows {
+			history[i] = bitemporal.Version[TimeTestSqlStruct]{Period: bitemporal.Period{From: r.TimeField, To: r.NullTime}, RecordedAt: r.CreatedAt, SupersededAt: r.UpdatedAt, Value: r}
+		}
+		return history
+	}
+	record := func(name string, period bitemporal.Period, recordedAt timi.Time) {
+		changes, err := bitemporal.InsertSuperseding(load(), bitemporal.Version[TimeTestSqlStruct]{
+			Period:     period,
+			RecordedAt: recordedAt,
+			Value:      TimeTestSqlStruct{Name: name},
+		})
+		if err != nil {
+			t.Fatalf("%s: InsertSuperseding %s failed: %v", dbName, name, err)
+		}
+		for _, v := range changes.Superseded {
+			if err = db.Model(&v.Value).UpdateColumn("updated_at", v.SupersededAt).Error; err != nil {
+				t.Fatalf("%s: Update error: %v", dbName, err)
+			}
+		}
+		for _, v := range changes.Inserted {
+			v.Value.ID = 0
+			v.Value.TimeField, v.Value.NullTime, v.Value.CreatedAt = v.From, v.To, v.RecordedAt
+			// Omit updated_at, which GORM would set to the current time
+			if err = db.Omit("updated_at").Create(&v.Value).Error; err != nil {
+				t.Fatalf("%s: Create error: %v", dbName, err)
+			}
+		}
+	}
+	names := func(p sqlfilter.Predicate) []string {
+		var found []TimeTestSqlStruct
+		if err := db.Where(p.String(), p.Args()...).Order("time_field").Find(&found).Error; err != nil {
+			t.Fatalf("%s: query error: %v", dbName, err)
+		}
+		var names []string
+		for _, r := range found {
+			names = append(names, r.Name)
+		}
+		return names
+	}
+
+	record("price_1", bitemporal.Period{From: day(1)}, day(1))
+	record("price_2", bitemporal.Period{From: day(10)}, day(10))
+	knownBefore := columns.AsOf(day(6), day(15))
+	if got := names(knownBefore); fmt.Sprint(got) != "[price_1]" {
+		t.Errorf("%s: Expected [price_1] before the correction, got %v", dbName, got)
+	}
+	// A correction recorded later splits price_1
+	record("price_fix", bitemporal.Period{From: day(5), To: day(8)}, day(20))
+
+	testCases := []struct {
+		name     string
+		p        sqlfilter.Predicate
+		expected []string
+	}{
+		// What was known before the correction is unchanged by it
+		{"KnownBeforeCorrection", knownBefore, []string{"price_1"}},
+		{"SplitKnownBefore", columns.AsOf(day(9), day(15)), []string{"price_1"}},
+		{"Now", columns.AsOf(day(6), timi.NilTime), []string{"price_fix"}},
+		{"SplitNow", columns.AsOf(day(9), timi.NilTime), []string{"price_1"}},
+		{"KnownAtCorrection", columns.AsOf(day(6), day(20)), []string{"price_fix"}},
+		{"Current", sqlfilter.And(columns.Current(), columns.KnownAt(timi.NilTime)), []string{"price_2"}},
+	}
+	for _, tc := range testCases {
+		if got := names(tc.p); fmt.Sprint(got) != fmt.Sprint(tc.expected) {
+			t.Errorf("%s: %s: expected %v, got %v", dbName, tc.name, tc.expected, got)
+		}
+	}
+
+	// Every row is kept, and the versions known now do not overlap
+	if history := load(); len(history) != 6 {
+		t.Errorf("%s: Expected 6 rows, got %d", dbName, len(history))
+	} else if err := bitemporal.Validate(history); err != nil {
+		t.Errorf("%s: Expected valid versions, got %v", dbName, err)
+	}
+}
+
+func testSQLPartialUpdate(t *testing.T, db *gorm.DB, dbName string) {
+	// Clear table
+	db.Exec("DELETE FROM timi_test")
+
+	ti := timi.Date(2024, time.January, 15, 10, 30, 45, 0, time.UTC)
+	record := TimeTestSqlStruct{Name: "partial_update", TimeField: ti, NullTime: ti}
+	if err := db.Create(&record).Error; err != nil {
+		t.Fatalf("%s: Failed to insert record: %v", dbName, err)
+	}
+
+	// The patch names its columns with gorm tags, as the models do; the
+	// field names alone would give start and end
+	type patch struct {
+		Start     timi.Optional `gorm:"column:time_field"`
+		End       timi.Optional `gorm:"column:null_time"`
+		CreatedAt timi.Optional `gorm:"column:created_at"`
+	}
+	later := ti.Add(time.Hour)
+	columns, err := timi.UpdateColumns(patch{Start: timi.OptionalOf(later), End: timi.OptionalNull()})
+	if err != nil {
+		t.Fatalf("%s: UpdateColumns failed: %v", dbName, err)
+	}
+	if err = db.Model(&TimeTestSqlStruct{}).Where("id = ?", record.ID).Updates(columns).Error; err != nil {
+		t.Fatalf("%s: Updates failed: %v", dbName, err)
+	}
+
+	var got TimeTestSqlStruct
+	if err = db.First(&got, record.ID).Error; err != nil {
+		t.Fatalf("%s: Failed to retrieve record: %v", dbName, err)
+	}
+	if !got.TimeField.Equal(later) || !got.NullTime.IsNull() || !timesApproximatelyEqual(got.CreatedAt, record.CreatedAt) {
+		t.Errorf("%s: Unexpected record after the partial update: %+v", dbName, got)
+	}
+}
@@ -0,0 +1,159 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/ieshan/timi"
+	timipgx "github.com/ieshan/timi/pgx"
+	"github.com/jackc/pgx/v5"
+	_ "github.com/jackc/pgx/v5/stdlib"
+)
+
+func TestPgx(t *testing.T) {
+	ctx := context.Background()
+	conn, err := pgx.Connect(ctx, "host=postgres user=postgres password=password port=5432 sslmode=disable TimeZone=Asia/Kolkata")
+	if err != nil {
+		t.Fatalf("PostgreSQL connection error: %v", err)
+	}
+	defer conn.Close(ctx)
+	timipgx.RegisterWithInfinity(conn.TypeMap(), timipgx.InfinityClamp)
+
+	if _, err = conn.Exec(ctx, `
+		CREATE TEMPORARY TABLE timi_pgx_test (
+			id BIGSERIAL PRIMARY KEY,
+			ts TIMESTAMP,
+			tstz TIMESTAMPTZ,
+			tstz_array TIMESTAMPTZ[]
+		);`); err != nil {
+		t.Fatalf("Table creation error: %v", err)
+	}
+
+	ti := timi.Date(2024, 1, 15, 10, 30, 45, 123456789, time.UTC)
+	expected := ti.Truncate(time.Microsecond)
+	values := []timi.Time{expected, timi.NilTime, timipgx.MaxTime, timipgx.MinTime}
+
+	for _, mode := range []pgx.QueryExecMode{pgx.QueryExecModeCacheStatement, pgx.QueryExecModeSimpleProtocol} {
+		if _, err = conn.Exec(ctx, "TRUNCATE timi_pgx_test"); err != nil {
+			t.Fatalf("Truncate error: %v", err)
+		}
+		if _, err = conn.Exec(ctx, "INSERT INTO timi_pgx_test (ts, tstz, tstz_array) VALUES ($1, $2, $3), ($4, $4, NULL)",
+			mode, ti, ti, values, timi.NilTime); err != nil {
+			t.Fatalf("Mode %v: insert error: %v", mode, err)
+		}
+
+		rows, err := conn.Query(ctx, "SELECT ts, tstz, tstz_array FROM timi_pgx_test ORDER BY id", mode)
+		if err != nil {
+			t.Fatalf("Mode %v: query error: %v", mode, err)
+		}
+		var got []struct {
+			ts, tstz timi.Time
+			array    []timi.Time
+		}
+		for rows.Next() {
+			var r struct {
+				ts, tstz timi.Time
+				array    []timi.Time
+			}
+			if err = rows.Scan(&r.ts, &r.tstz, &r.array); err != nil {
+				t.Fatalf("Mode %v: scan error: %v", mode, err)
+			}
+			got = append(got, r)
+		}
+		if err = rows.Err(); err != nil {
+			t.Fatalf("Mode %v: rows error: %v", mode, err)
+		}
+		if len(got) != 2 {
+			t.Fatalf("Mode %v: expected 2 rows, got %d", mode, len(got))
+		}
+		if !got[0].ts.Equal(expected) || !got[0].tstz.Equal(expected) {
+			t.Errorf("Mode %v: expected %v, got %v and %v", mode, expected, got[0].ts, got[0].tstz)
+		}
+		if len(got[0].array) != len(values) {
+			t.Fatalf("Mode %v: expected %d array elements, got %v", mode, len(values), got[0].array)
+		}
+		for i := range values {
+			if got[0].array[i].Valid != values[i].Valid || !got[0].array[i].Equal(values[i]) {
+				t.Errorf("Mode %v: element %d: expected %v, got %v", mode, i, values[i], got[0].array[i])
+			}
+		}
+		if got[1].ts.Valid || got[1].tstz.Valid || got[1].array != nil {
+			t.Errorf("Mode %v: expected nulls, got %+v", mode, got[1])
+		}
+	}
+
+	// A timestamp column stores the UTC wall clock regardless of the session time zone
+	var wall string
+	if err = conn.QueryRow(ctx, "SELECT ts::text FROM timi_pgx_test ORDER BY id LIMIT 1").Scan(&wall); err != nil {
+		t.Fatalf("Query error: %v", err)
+	}
+	if wall != "2024-01-15 10:30:45.123456" {
+		t.Errorf("Expected the UTC wall clock, got %s", wall)
+	}
+
+	// infinity maps to MaxTime and -infinity to MinTime
+	var pos, neg timi.Time
+	if err = conn.QueryRow(ctx, "SELECT 'infinity'::timestamptz, '-infinity'::timestamp").Scan(&pos, &neg); err != nil {
+		t.Fatalf("Infinity query error: %v", err)
+	}
+	if !pos.Equal(timipgx.MaxTime) || !neg.Equal(timipgx.MinTime) {
+		t.Errorf("Expected %v and %v, got %v and %v", timipgx.MaxTime, timipgx.MinTime, pos, neg)
+	}
+	var isInf bool
+	if err = conn.QueryRow(ctx, "SELECT $1::timestamptz = 'infinity'", timipgx.MaxTime).Scan(&isInf); err != nil || !isInf {
+		t.Errorf("Expected MaxTime to be sent as infinity, got %v, %v", isInf, err)
+	}
+}
+
+func TestPgxInfinityDatabaseSQL(t *testing.T) {
+	db, err := sql.Open("pgx", "host=postgres user=postgres password=password port=5432 sslmode=disable")
+	if err != nil {
+		t.Fatalf("PostgreSQL connection error: %v", err)
+	}
+	defer db.Close()
+
+	ctx := context.Background()
+	conn, err := db.Conn(ctx)
+	if err != nil {
+		t.Fatalf("PostgreSQL connection error: %v", err)
+	}
+	defer conn.Close()
+	if _, err = conn.ExecContext(ctx, "CREATE TEMPORARY TABLE timi_validity (valid_from TIMESTAMPTZ, valid_to TIMESTAMPTZ)"); err != nil {
+		t.Fatalf("Table creation error: %v", err)
+	}
+
+	// Value writes the sentinels as 'infinity' and '-infinity'
+	from := timi.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC)
+	if _, err = conn.ExecContext(ctx, "INSERT INTO timi_validity VALUES ($1, $2), ($3, $4)",
+		from, timi.Infinity, timi.NegInfinity, from); err != nil {
+		t.Fatalf("Insert error: %v", err)
+	}
+	var count int
+	if err = conn.QueryRowContext(ctx, "SELECT count(*) FROM timi_validity WHERE valid_to = 'infinity' OR valid_from = '-infinity'").Scan(&count); err != nil || count != 2 {
+		t.Fatalf("Expected 2 infinite rows, got %d, %v", count, err)
+	}
+
+	// Scan reads them back, and they order around finite times
+	rows, err := conn.QueryContext(ctx, "SELECT valid_from, valid_to FROM timi_validity ORDER BY valid_from")
+	if err != nil {
+		t.Fatalf("Query error: %v", err)
+	}
+	defer rows.Close()
+	var got [][2]timi.Time
+	for rows.Next() {
+		var r [2]timi.Time
+		if err = rows.Scan(&r[0], &r[1]); err != nil {
+			t.Fatalf("Scan error: %v", err)
+		}
+		got = append(got, r)
+	}
+	if len(got) != 2 || got[0][0] != timi.NegInfinity || !got[0][1].Equal(from) ||
+		!got[1][0].Equal(from) || got[1][1] != timi.Infinity {
+		t.Fatalf("Unexpected rows %v", got)
+	}
+	if !got[1][1].After(got[1][0]) || !got[0][0].Before(got[0][1]) {
+		t.Errorf("Expected the infinities to compare around %v", from)
+	}
+}
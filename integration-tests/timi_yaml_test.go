@@ -0,0 +1,61 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ieshan/timi"
+	"gopkg.in/yaml.v3"
+)
+
+type TimeTestYAMLStruct struct {
+	TimeField timi.Time  `yaml:"time_field"`
+	ZeroTime  timi.Time  `yaml:"zero_time,omitempty"`
+	NullTime  timi.Time  `yaml:"null_time"`
+	Omitted   timi.Time  `yaml:"omitted,omitempty"`
+	Pointer   *timi.Time `yaml:"pointer,omitempty"`
+}
+
+func TestYAML(t *testing.T) {
+	ti := timi.Date(2024, 1, 15, 12, 30, 45, 123456789, time.UTC)
+	doc := TimeTestYAMLStruct{
+		TimeField: ti,
+		ZeroTime:  timi.Time{Time: time.Time{}, Valid: true},
+		NullTime:  timi.NilTime,
+		Omitted:   timi.NilTime,
+	}
+
+	data, err := yaml.Marshal(doc)
+	if err != nil {
+		t.Fatalf("Marshal failed: %v", err)
+	}
+	// A valid zero instant is emitted, and omitempty omits null times
+	expected := "time_field: \"2024-01-15T12:30:45.123456789Z\"\n" +
+		"zero_time: \"0001-01-01T00:00:00Z\"\n" +
+		"null_time: null\n"
+	if string(data) != expected {
+		t.Fatalf("Expected %q, got %q", expected, data)
+	}
+
+	var decoded TimeTestYAMLStruct
+	if err = yaml.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("Unmarshal failed: %v", err)
+	}
+	if !decoded.TimeField.Equal(ti) {
+		t.Errorf("TimeField: expected %v, got %v", ti, decoded.TimeField)
+	}
+	if !decoded.ZeroTime.Valid || !decoded.ZeroTime.Time.IsZero() {
+		t.Errorf("ZeroTime: expected the valid zero instant, got %v", decoded.ZeroTime)
+	}
+	if decoded.NullTime.Valid || decoded.Omitted.Valid || decoded.Pointer != nil {
+		t.Errorf("Expected null times, got %+v", decoded)
+	}
+
+	// Unquoted timestamps and explicit nulls decode as well
+	if err = yaml.Unmarshal([]byte("time_field: 2024-01-15T12:30:45Z\nnull_time: ~\n"), &decoded); err != nil {
+		t.Fatalf("Unmarshal failed: %v", err)
+	}
+	if !decoded.TimeField.Equal(ti.Truncate(time.Second)) || decoded.NullTime.Valid {
+		t.Errorf("Expected %v and null, got %+v", ti.Truncate(time.Second), decoded)
+	}
+}
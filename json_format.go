@@ -0,0 +1,192 @@
+package timi
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// jsonFormat describes how a valid Time is represented in JSON.
+// A positive pow10 selects a decimal number of seconds (1), milliseconds (1e3),
+// microseconds (1e6) or nanoseconds (1e9) since the Unix epoch, otherwise
+// layout is used to produce a JSON string. An empty layout means RFC 3339
+// with nanoseconds, which is also what MarshalJSON produces.
+type jsonFormat struct {
+	layout string
+	pow10  int64
+}
+
+// parseJSONFormat accepts the same format names as the json/v2 `format`
+// struct tag option for time.Time.
+func parseJSONFormat(format string) (jsonFormat, error) {
+	switch format {
+	case "":
+		return jsonFormat{}, nil
+	case "ANSIC":
+		return jsonFormat{layout: time.ANSIC}, nil
+	case "UnixDate":
+		return jsonFormat{layout: time.UnixDate}, nil
+	case "RubyDate":
+		return jsonFormat{layout: time.RubyDate}, nil
+	case "RFC822":
+		return jsonFormat{layout: time.RFC822}, nil
+	case "RFC822Z":
+		return jsonFormat{layout: time.RFC822Z}, nil
+	case "RFC850":
+		return jsonFormat{layout: time.RFC850}, nil
+	case "RFC1123":
+		return jsonFormat{layout: time.RFC1123}, nil
+	case "RFC1123Z":
+		return jsonFormat{layout: time.RFC1123Z}, nil
+	case "RFC3339":
+		return jsonFormat{layout: time.RFC3339}, nil
+	case "RFC3339Nano":
+		return jsonFormat{}, nil
+	case "Kitchen":
+		return jsonFormat{layout: time.Kitchen}, nil
+	case "Stamp":
+		return jsonFormat{layout: time.Stamp}, nil
+	case "StampMilli":
+		return jsonFormat{layout: time.StampMilli}, nil
+	case "StampMicro":
+		return jsonFormat{layout: time.StampMicro}, nil
+	case "StampNano":
+		return jsonFormat{layout: time.StampNano}, nil
+	case "DateTime":
+		return jsonFormat{layout: time.DateTime}, nil
+	case "DateOnly":
+		return jsonFormat{layout: time.DateOnly}, nil
+	case "TimeOnly":
+		return jsonFormat{layout: time.TimeOnly}, nil
+	case "unix":
+		return jsonFormat{pow10: 1}, nil
+	case "unixmilli":
+		return jsonFormat{pow10: 1e3}, nil
+	case "unixmicro":
+		return jsonFormat{pow10: 1e6}, nil
+	case "unixnano":
+		return jsonFormat{pow10: 1e9}, nil
+	}
+	// Like json/v2, reject unknown identifiers so that new names can be added later.
+	if strings.TrimFunc(format, func(r rune) bool {
+		return 'a' <= r && r <= 'z' || 'A' <= r && r <= 'Z' || '0' <= r && r <= '9'
+	}) == "" {
+		return jsonFormat{}, fmt.Errorf("timi: invalid JSON format %q", format)
+	}
+	return jsonFormat{layout: format}, nil
+}
+
+// appendJSON appends t to b as a JSON value.
+func (f jsonFormat) appendJSON(b []byte, t Time) ([]byte, error) {
+	if !t.Valid {
+		return append(b, "null"...), nil
+	}
+	if inf := t.infinityJSON(); inf != "" {
+		return append(b, inf...), nil
+	}
+	switch {
+	case f.pow10 > 0:
+		return appendUnixDecimal(b, t.Time, f.pow10), nil
+	case f.layout == "":
+		return t.AppendJSON(b)
+	default:
+		return strconv.AppendQuote(b, t.Time.Format(f.layout)), nil
+	}
+}
+
+// parseJSON parses a JSON token of the given kind ('n', '"' or '0') into a
+// Time. For strings, s is the unquoted value; for numbers, the literal.
+func (f jsonFormat) parseJSON(kind byte, s string) (Time, error) {
+	var (
+		tv  time.Time
+		err error
+	)
+	switch {
+	case kind == 'n':
+		return NilTime, nil
+	case kind != '"' && kind != '0':
+		return NilTime, fmt.Errorf("timi: cannot unmarshal JSON %c into timi.Time", kind)
+	}
+	if inf, ok := parseInfinityToken(kind, s); ok {
+		return inf, nil
+	}
+	switch {
+	case f.pow10 > 0:
+		tv, err = parseUnixDecimal(s, f.pow10)
+	case kind != '"':
+		return NilTime, errors.New("timi: cannot unmarshal JSON number into timi.Time")
+	case f.layout == "":
+		var ok bool
+		if tv, ok = parseRFC3339([]byte(s)); !ok {
+			err = tv.UnmarshalText([]byte(s))
+		}
+	default:
+		tv, err = time.Parse(f.layout, s)
+	}
+	if err != nil {
+		return NilTime, err
+	}
+	return Time{Time: tv.UTC(), Valid: true}, nil
+}
+
+// appendUnixDecimal appends tv as a decimal number of units since the Unix
+// epoch, where pow10 is the number of units per second. Sub-unit precision is
+// kept as a fraction with trailing zeros removed.
+func appendUnixDecimal(b []byte, tv time.Time, pow10 int64) []byte {
+	sec, nsec := tv.Unix(), int64(tv.Nanosecond())
+	if sec < 0 {
+		b = append(b, '-')
+		if sec, nsec = -sec, -nsec; nsec < 0 {
+			sec, nsec = sec-1, nsec+int64(time.Second)
+		}
+	}
+	unit := int64(time.Second) / pow10
+	whole, frac := nsec/unit, nsec%unit*pow10
+	if sec == 0 {
+		b = strconv.AppendInt(b, whole, 10)
+	} else {
+		b = strconv.AppendInt(b, sec, 10)
+		if pow10 > 1 {
+			b = appendPaddedInt(b, whole, len(strconv.FormatInt(pow10, 10))-1)
+		}
+	}
+	if frac != 0 {
+		b = bytes.TrimRight(appendPaddedInt(append(b, '.'), frac, 9), "0")
+	}
+	return b
+}
+
+// parseUnixDecimal is the inverse of appendUnixDecimal.
+// Fractional digits beyond nanosecond precision are truncated.
+func parseUnixDecimal(s string, pow10 int64) (time.Time, error) {
+	digits, neg := strings.CutPrefix(s, "-")
+	wholeStr, fracStr, hasFrac := strings.Cut(digits, ".")
+	whole, err := strconv.ParseUint(wholeStr, 10, 63)
+	if err != nil || hasFrac && fracStr == "" || strings.TrimLeft(fracStr, "0123456789") != "" {
+		return time.Time{}, fmt.Errorf("timi: invalid Unix time %q", s)
+	}
+	var frac int64
+	for i := range 9 {
+		frac *= 10
+		if i < len(fracStr) {
+			frac += int64(fracStr[i] - '0')
+		}
+	}
+	sec := int64(whole) / pow10
+	nsec := int64(whole)%pow10*(int64(time.Second)/pow10) + frac/pow10
+	if neg {
+		sec, nsec = -sec, -nsec
+	}
+	return time.Unix(sec, nsec).UTC(), nil
+}
+
+func appendPaddedInt(b []byte, n int64, width int) []byte {
+	s := strconv.FormatInt(n, 10)
+	for range width - len(s) {
+		b = append(b, '0')
+	}
+	return append(b, s...)
+}
@@ -0,0 +1,72 @@
+//go:build goexperiment.jsonv2 || go1.27
+
+// encoding/json/v2 is part of the standard library from Go 1.27 on, and an
+// experiment enabled with GOEXPERIMENT=jsonv2 before. This file refers to it
+// through the names declared in json_v2_std.go and json_v2_experiment.go.
+
+package timi
+
+func (f jsonFormat) marshal(enc *jsonEncoder, t Time) error {
+	var buf [64]byte
+	b, err := f.appendJSON(buf[:0], t)
+	if err != nil {
+		return err
+	}
+	return enc.WriteValue(b)
+}
+
+func (f jsonFormat) unmarshal(dec *jsonDecoder, t *Time) error {
+	tok, err := dec.ReadToken()
+	if err != nil {
+		return err
+	}
+	tv, err := f.parseJSON(byte(tok.Kind()), tok.String())
+	if err != nil {
+		return err
+	}
+	*t = tv
+	return nil
+}
+
+// MarshalJSONTo implements the json.MarshalerTo interface from encoding/json/v2;
+// enc is a *jsontext.Encoder.
+// A null Time is encoded as JSON null and a valid Time as an RFC 3339 string,
+// the same as MarshalJSON.
+func (t Time) MarshalJSONTo(enc *jsonEncoder) error {
+	return jsonFormat{}.marshal(enc, t)
+}
+
+// UnmarshalJSONFrom implements the json.UnmarshalerFrom interface from encoding/json/v2;
+// dec is a *jsontext.Decoder.
+// JSON null decodes to NilTime.
+func (t *Time) UnmarshalJSONFrom(dec *jsonDecoder) error {
+	return jsonFormat{}.unmarshal(dec, t)
+}
+
+// JSONFormat returns encoding/json/v2 json.Options that encode and decode every
+// timi.Time using format. The accepted names are those of the json/v2
+// `format` struct tag option for time.Time: a time package layout constant
+// such as "RFC3339" or "DateTime", "unix", "unixmilli", "unixmicro",
+// "unixnano", or a custom layout. Null times are always JSON null.
+//
+// json/v2 rejects the `format` tag option on types with their own JSON
+// methods, so this is the way to select a format for timi.Time:
+//
+//	json.Marshal(v, timi.JSONFormat("unixmilli"))
+func JSONFormat(format string) jsonOptions {
+	f, err := parseJSONFormat(format)
+	return jsonTimeOptions(
+		func(enc *jsonEncoder, t Time) error {
+			if err != nil {
+				return err
+			}
+			return f.marshal(enc, t)
+		},
+		func(dec *jsonDecoder, t *Time) error {
+			if err != nil {
+				return err
+			}
+			return f.unmarshal(dec, t)
+		},
+	)
+}
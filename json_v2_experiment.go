@@ -0,0 +1,22 @@
+//go:build goexperiment.jsonv2 && !go1.27
+
+// The encoding/json/v2 names used by json_v2.go, in GOEXPERIMENT=jsonv2 builds
+// before Go 1.27; see json_v2_std.go.
+
+package timi
+
+import (
+	"encoding/json/jsontext"
+	"encoding/json/v2"
+)
+
+type (
+	jsonEncoder = jsontext.Encoder
+	jsonDecoder = jsontext.Decoder
+	jsonOptions = json.Options
+)
+
+// jsonTimeOptions returns options that encode and decode Time with m and u.
+func jsonTimeOptions(m func(*jsonEncoder, Time) error, u func(*jsonDecoder, *Time) error) jsonOptions {
+	return json.JoinOptions(json.WithMarshalers(json.MarshalToFunc(m)), json.WithUnmarshalers(json.UnmarshalFromFunc(u)))
+}
@@ -0,0 +1,25 @@
+//go:build go1.27
+
+// The encoding/json/v2 names used by json_v2.go, in the standard library from
+// Go 1.27 on. Referring to them from a file whose build constraint implies
+// go1.27 keeps the stdversion check of go vet and go test satisfied, which
+// the constraint of json_v2.go cannot do; json_v2_experiment.go declares the
+// same names for GOEXPERIMENT=jsonv2 builds before Go 1.27.
+
+package timi
+
+import (
+	"encoding/json/jsontext"
+	"encoding/json/v2"
+)
+
+type (
+	jsonEncoder = jsontext.Encoder
+	jsonDecoder = jsontext.Decoder
+	jsonOptions = json.Options
+)
+
+// jsonTimeOptions returns options that encode and decode Time with m and u.
+func jsonTimeOptions(m func(*jsonEncoder, Time) error, u func(*jsonDecoder, *Time) error) jsonOptions {
+	return json.JoinOptions(json.WithMarshalers(json.MarshalToFunc(m)), json.WithUnmarshalers(json.UnmarshalFromFunc(u)))
+}
@@ -0,0 +1,156 @@
+//go:build go1.27
+
+package timi
+
+import (
+	"encoding/json/v2"
+	"testing"
+	"time"
+)
+
+func TestTime_MarshalJSONTo(t *testing.T) {
+	type TimeTestStruct struct {
+		Time1 Time `json:"time1"`
+		Time2 Time `json:"time2"`
+	}
+	ti := Date(2021, 1, 1, 0, 0, 0, 123000000, time.UTC)
+	testCases := []struct {
+		format   string
+		expected string
+	}{
+		{"", `{"time1":"2021-01-01T00:00:00.123Z","time2":null}`},
+		{"RFC3339", `{"time1":"2021-01-01T00:00:00Z","time2":null}`},
+		{"DateOnly", `{"time1":"2021-01-01","time2":null}`},
+		{"unix", `{"time1":1609459200.123,"time2":null}`},
+		{"unixmilli", `{"time1":1609459200123,"time2":null}`},
+		{"unixmicro", `{"time1":1609459200123000,"time2":null}`},
+		{"unixnano", `{"time1":1609459200123000000,"time2":null}`},
+	}
+	for _, tc := range testCases {
+		t.Run("Format"+tc.format, func(t *testing.T) {
+			var opts []json.Options
+			if tc.format != "" {
+				opts = append(opts, JSONFormat(tc.format))
+			}
+			jsonVal, err := json.Marshal(&TimeTestStruct{Time1: ti, Time2: NilTime}, opts...)
+			if err != nil {
+				t.Fatalf("Got error while marshaling to JSON %v", err)
+			}
+			if string(jsonVal) != tc.expected {
+				t.Fatalf("Expected %s, got %s", tc.expected, jsonVal)
+			}
+
+			var unmVal TimeTestStruct
+			if err = json.Unmarshal(jsonVal, &unmVal, opts...); err != nil {
+				t.Fatalf("Got error while unmarshaling JSON %v", err)
+			}
+			expected := ti
+			if tc.format == "RFC3339" || tc.format == "DateOnly" {
+				expected = ti.Truncate(time.Second)
+				if tc.format == "DateOnly" {
+					expected = ti.Truncate(24 * time.Hour)
+				}
+			}
+			if unmVal.Time1.IsNull() || !unmVal.Time1.Equal(expected) {
+				t.Fatalf("Expected %v, got %v", expected, unmVal.Time1)
+			}
+			if unmVal.Time1.Time.Location() != time.UTC {
+				t.Fatalf("Expected UTC, got %v", unmVal.Time1.Time.Location())
+			}
+			if !unmVal.Time2.IsNull() {
+				t.Fatalf("Time2 should be null but got %v", unmVal.Time2)
+			}
+		})
+	}
+}
+
+func TestTime_UnmarshalJSONFrom(t *testing.T) {
+	testCases := []struct {
+		name     string
+		format   string
+		json     string
+		expected Time
+		hasErr   bool
+	}{
+		{"RFC3339", "", `"2021-01-01T02:00:00+02:00"`, Date(2021, 1, 1, 0, 0, 0, 0, time.UTC), false},
+		{"Null", "", `null`, NilTime, false},
+		{"EmptyString", "", `""`, NilTime, true},
+		{"NullString", "", `"null"`, NilTime, true},
+		{"Number", "", `1609459200`, NilTime, true},
+		{"NegativeUnix", "unix", `-1.5`, Date(1969, 12, 31, 23, 59, 58, 500000000, time.UTC), false},
+		{"UnixMilliFraction", "unixmilli", `1609459200123.456`, Date(2021, 1, 1, 0, 0, 0, 123456000, time.UTC), false},
+		{"UnixMilliNull", "unixmilli", `null`, NilTime, false},
+		{"UnixMilliString", "unixmilli", `"1609459200123"`, Date(2021, 1, 1, 0, 0, 0, 123000000, time.UTC), false},
+		{"UnixInvalid", "unix", `"abc"`, NilTime, true},
+		{"UnknownFormat", "NotAFormat", `"2021-01-01"`, NilTime, true},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var opts []json.Options
+			if tc.format != "" {
+				opts = append(opts, JSONFormat(tc.format))
+			}
+			unmVal := Now()
+			err := json.Unmarshal([]byte(tc.json), &unmVal, opts...)
+			if tc.hasErr {
+				if err == nil {
+					t.Fatalf("Expecting error, but got nil")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Got error while unmarshaling JSON %v", err)
+			}
+			if unmVal.IsNull() != tc.expected.IsNull() || !unmVal.Equal(tc.expected) {
+				t.Fatalf("Expected %v, got %v", tc.expected, unmVal)
+			}
+		})
+	}
+}
+
+func TestTime_MarshalJSONToOmit(t *testing.T) {
+	type TimeTestStruct struct {
+		Zero  Time `json:"zero,omitzero"`
+		Null  Time `json:"null,omitzero"`
+		Empty Time `json:"empty,omitempty"`
+		Kept  Time `json:"kept"`
+	}
+	jsonVal, err := json.Marshal(TimeTestStruct{
+		Zero:  Time{Time: time.Time{}, Valid: true},
+		Null:  NilTime,
+		Empty: NilTime,
+		Kept:  NilTime,
+	})
+	if err != nil {
+		t.Fatalf("Got error while marshaling to JSON %v", err)
+	}
+	// In v2, omitempty omits values encoded as JSON null, so it omits null
+	// times as well
+	expected := `{"zero":"0001-01-01T00:00:00Z","kept":null}`
+	if string(jsonVal) != expected {
+		t.Fatalf("Expected %s, got %s", expected, jsonVal)
+	}
+}
+
+func TestTime_MarshalJSONToInfinity(t *testing.T) {
+	for _, format := range []string{"", "RFC3339", "unixmilli"} {
+		var opts []json.Options
+		if format != "" {
+			opts = append(opts, JSONFormat(format))
+		}
+		jsonVal, err := json.Marshal([]Time{Infinity, NegInfinity}, opts...)
+		if err != nil {
+			t.Fatalf("%s: got error while marshaling to JSON %v", format, err)
+		}
+		if string(jsonVal) != `["infinity","-infinity"]` {
+			t.Fatalf("%s: expected %s, got %s", format, `["infinity","-infinity"]`, jsonVal)
+		}
+		var unmVal []Time
+		if err = json.Unmarshal(jsonVal, &unmVal, opts...); err != nil {
+			t.Fatalf("%s: got error while unmarshaling JSON %v", format, err)
+		}
+		if unmVal[0] != Infinity || unmVal[1] != NegInfinity {
+			t.Fatalf("%s: expected the infinities, got %v", format, unmVal)
+		}
+	}
+}
@@ -0,0 +1,264 @@
+package mongodb
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/ieshan/timi"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+// Aggregation Helpers for timi.Time
+// The stage builders group dates by calendar units in an IANA time zone with
+// $dateTrunc, $dateDiff and $bucket. Weeks start on Monday, as in ISO 8601.
+// TruncateIn and DateBoundaries compute the same bucket starts in Go, so
+// bounds and empty buckets can be matched against the pipeline results.
+// Bucket keys are BSON Dates holding the UTC instant of the bucket start.
+//
+// MongoDB accepts IANA zone names and UTC offsets as timezone. Locations
+// from time.LoadLocation are passed by name and fixed zones, such as those
+// of time.FixedZone, as a "+hh:mm" offset. time.Local and other locations
+// without an IANA name are an error.
+
+// Unit is a calendar unit for date bucketing.
+type Unit string
+
+const (
+	Minute  Unit = "minute"
+	Hour    Unit = "hour"
+	Day     Unit = "day"
+	Week    Unit = "week"
+	Month   Unit = "month"
+	Quarter Unit = "quarter"
+	Year    Unit = "year"
+)
+
+// zoneName returns the timezone passed to MongoDB for loc: its IANA name, or
+// a "+hh:mm" offset for a fixed zone. A nil loc is UTC.
+func zoneName(loc *time.Location) (string, error) {
+	if loc == nil || loc == time.UTC {
+		return "UTC", nil
+	}
+	name := loc.String()
+	if loc == time.Local || name == "Local" {
+		return "", errors.New("timi/mongodb: time.Local has no IANA name; use time.LoadLocation")
+	}
+	// A fixed zone may reuse a zone name, as time.FixedZone("CET", 3600)
+	// does, so the name is used only if the zone it names has the same
+	// offsets
+	if named, err := time.LoadLocation(name); err == nil && name != "" && sameOffsets(loc, named) {
+		return name, nil
+	}
+	if offset, ok := fixedOffset(loc); ok && offset%60 == 0 {
+		sign := '+'
+		if offset < 0 {
+			sign, offset = '-', -offset
+		}
+		return fmt.Sprintf("%c%02d:%02d", sign, offset/3600, offset/60%60), nil
+	}
+	return "", fmt.Errorf("timi/mongodb: time zone %q is neither an IANA name nor a fixed offset", name)
+}
+
+// offsetSamples are the instants at which zone offsets are compared: January
+// and July of every year from 1970 to 2037.
+var offsetSamples = func() []time.Time {
+	var ts []time.Time
+	for year := 1970; year <= 2037; year++ {
+		ts = append(ts, time.Date(year, time.January, 1, 0, 0, 0, 0, time.UTC), time.Date(year, time.July, 1, 0, 0, 0, 0, time.UTC))
+	}
+	return ts
+}()
+
+func sameOffsets(a, b *time.Location) bool {
+	for _, t := range offsetSamples {
+		_, oa := t.In(a).Zone()
+		_, ob := t.In(b).Zone()
+		if oa != ob {
+			return false
+		}
+	}
+	return true
+}
+
+// fixedOffset returns the offset of loc in seconds, if it does not change.
+func fixedOffset(loc *time.Location) (int, bool) {
+	_, offset := offsetSamples[0].In(loc).Zone()
+	return offset, sameOffsets(loc, time.FixedZone("", offset))
+}
+
+// dateExpr converts timi.Time arguments to BSON Date, or null for
+// timi.NilTime. Other expressions, such as "$created_at", are kept as is.
+func dateExpr(v any) any {
+	if t, ok := v.(timi.Time); ok {
+		if !t.Valid {
+			return nil
+		}
+		return dateTime(t)
+	}
+	return v
+}
+
+// DateTrunc returns a $dateTrunc expression truncating date to unit in loc.
+// date is an expression such as "$created_at", or a timi.Time.
+func DateTrunc(date any, unit Unit, loc *time.Location) (bson.D, error) {
+	zone, err := zoneName(loc)
+	if err != nil {
+		return nil, err
+	}
+	args := bson.D{
+		{Key: "date", Value: dateExpr(date)},
+		{Key: "unit", Value: string(unit)},
+		{Key: "timezone", Value: zone},
+	}
+	if unit == Week {
+		args = append(args, bson.E{Key: "startOfWeek", Value: "monday"})
+	}
+	return bson.D{{Key: "$dateTrunc", Value: args}}, nil
+}
+
+// DateDiff returns a $dateDiff expression counting the unit boundaries
+// crossed from start to end in loc. start and end are expressions such as
+// "$created_at", or timi.Time values.
+func DateDiff(start, end any, unit Unit, loc *time.Location) (bson.D, error) {
+	zone, err := zoneName(loc)
+	if err != nil {
+		return nil, err
+	}
+	args := bson.D{
+		{Key: "startDate", Value: dateExpr(start)},
+		{Key: "endDate", Value: dateExpr(end)},
+		{Key: "unit", Value: string(unit)},
+		{Key: "timezone", Value: zone},
+	}
+	if unit == Week {
+		args = append(args, bson.E{Key: "startOfWeek", Value: "monday"})
+	}
+	return bson.D{{Key: "$dateDiff", Value: args}}, nil
+}
+
+// MatchBetween returns a $match stage keeping documents where field lies in
+// [from, to). A null bound is unbounded.
+func MatchBetween(field string, from, to timi.Time) bson.D {
+	return bson.D{{Key: "$match", Value: Between(field, from, to, ClosedOpen)}}
+}
+
+// GroupByDate returns a $group stage grouping documents by field truncated to
+// unit in loc. The bucket start is the _id of each group, and accumulators
+// are added as the other fields, for example
+// bson.D{{Key: "count", Value: bson.D{{Key: "$sum", Value: 1}}}}.
+// Documents with a null or missing field are grouped under a null _id.
+func GroupByDate(field string, unit Unit, loc *time.Location, accumulators bson.D) (bson.D, error) {
+	trunc, err := DateTrunc("$"+field, unit, loc)
+	if err != nil {
+		return nil, err
+	}
+	group := append(bson.D{{Key: "_id", Value: trunc}}, accumulators...)
+	return bson.D{{Key: "$group", Value: group}}, nil
+}
+
+// BucketByDate returns a $bucket stage grouping documents by field into the
+// buckets [boundaries[i], boundaries[i+1]). Documents outside of the
+// boundaries, or with a null or missing field, go to a bucket with a null
+// _id. output holds the accumulators; MongoDB counts the documents when it
+// is empty.
+func BucketByDate(field string, boundaries []timi.Time, output bson.D) bson.D {
+	bounds := make(bson.A, len(boundaries))
+	for i, b := range boundaries {
+		bounds[i] = dateExpr(b)
+	}
+	bucket := bson.D{
+		{Key: "groupBy", Value: "$" + field},
+		{Key: "boundaries", Value: bounds},
+		{Key: "default", Value: nil},
+	}
+	if len(output) > 0 {
+		bucket = append(bucket, bson.E{Key: "output", Value: output})
+	}
+	return bson.D{{Key: "$bucket", Value: bucket}}
+}
+
+// TruncateIn truncates t to the start of its unit in loc, matching
+// $dateTrunc. A nil loc is UTC. A null t returns timi.NilTime.
+func TruncateIn(t timi.Time, unit Unit, loc *time.Location) (timi.Time, error) {
+	if !t.Valid {
+		return timi.NilTime, nil
+	}
+	if loc == nil {
+		loc = time.UTC
+	}
+	lt := t.Time.In(loc)
+	year, month, day := lt.Date()
+	var tv time.Time
+	switch unit {
+	case Minute:
+		tv = lt.Add(-time.Duration(lt.Second())*time.Second - time.Duration(lt.Nanosecond()))
+	case Hour:
+		tv = lt.Add(-time.Duration(lt.Minute())*time.Minute - time.Duration(lt.Second())*time.Second - time.Duration(lt.Nanosecond()))
+	case Day:
+		tv = time.Date(year, month, day, 0, 0, 0, 0, loc)
+	case Week:
+		tv = time.Date(year, month, day-(int(lt.Weekday())+6)%7, 0, 0, 0, 0, loc)
+	case Month:
+		tv = time.Date(year, month, 1, 0, 0, 0, 0, loc)
+	case Quarter:
+		tv = time.Date(year, (month-1)/3*3+1, 1, 0, 0, 0, 0, loc)
+	case Year:
+		tv = time.Date(year, time.January, 1, 0, 0, 0, 0, loc)
+	default:
+		return timi.NilTime, fmt.Errorf("timi/mongodb: unsupported unit %q", unit)
+	}
+	return timi.Time{Time: tv.UTC(), Valid: true}, nil
+}
+
+// DateBoundaries returns the starts of the unit buckets in loc covering
+// [from, to), followed by the end of the last bucket, for use with
+// BucketByDate. Both bounds must be valid and from must be before to, so
+// that there are at least two boundaries, as $bucket requires.
+func DateBoundaries(from, to timi.Time, unit Unit, loc *time.Location) ([]timi.Time, error) {
+	if !from.Valid || !to.Valid {
+		return nil, errors.New("timi/mongodb: date boundaries need valid bounds")
+	}
+	if !from.Before(to) {
+		return nil, fmt.Errorf("timi/mongodb: date boundaries need from before to, got %v and %v", from, to)
+	}
+	if loc == nil {
+		loc = time.UTC
+	}
+	start, err := TruncateIn(from, unit, loc)
+	if err != nil {
+		return nil, err
+	}
+	boundaries := []timi.Time{start}
+	for b := start; b.Before(to); {
+		lb := b.Time.In(loc)
+		switch unit {
+		case Minute:
+			lb = lb.Add(time.Minute)
+		case Hour:
+			lb = lb.Add(time.Hour)
+		case Day:
+			lb = lb.AddDate(0, 0, 1)
+		case Week:
+			lb = lb.AddDate(0, 0, 7)
+		case Month:
+			lb = lb.AddDate(0, 1, 0)
+		case Quarter:
+			lb = lb.AddDate(0, 3, 0)
+		case Year:
+			lb = lb.AddDate(1, 0, 0)
+		}
+		// Truncate again so days stay at midnight across DST changes
+		if b, err = TruncateIn(timi.Time{Time: lb, Valid: true}, unit, loc); err != nil {
+			return nil, err
+		}
+		boundaries = append(boundaries, b)
+	}
+	return boundaries, nil
+}
+
+// DecodeBucketKey decodes the _id of a GroupByDate or BucketByDate result.
+// The null bucket returns timi.NilTime.
+func DecodeBucketKey(key bson.RawValue) (timi.Time, error) {
+	return StrictDecoder().decode(key)
+}
@@ -0,0 +1,248 @@
+package mongodb
+
+import (
+	"testing"
+	"time"
+	_ "time/tzdata"
+
+	"github.com/ieshan/timi"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+func mustLoadLocation(t *testing.T, name string) *time.Location {
+	t.Helper()
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		t.Fatalf("LoadLocation %s failed: %v", name, err)
+	}
+	return loc
+}
+
+func TestTruncateIn(t *testing.T) {
+	ny := mustLoadLocation(t, "America/New_York")
+	kolkata := mustLoadLocation(t, "Asia/Kolkata")
+	// Wednesday 2024-05-15 02:47:31.5 UTC is Tuesday 22:47:31.5 in New York
+	ti := timi.Date(2024, time.May, 15, 2, 47, 31, 500000000, time.UTC)
+
+	testCases := []struct {
+		unit     Unit
+		loc      *time.Location
+		expected timi.Time
+	}{
+		{Minute, ny, timi.Date(2024, time.May, 14, 22, 47, 0, 0, ny)},
+		{Hour, ny, timi.Date(2024, time.May, 14, 22, 0, 0, 0, ny)},
+		{Hour, kolkata, timi.Date(2024, time.May, 15, 8, 0, 0, 0, kolkata)},
+		{Day, ny, timi.Date(2024, time.May, 14, 0, 0, 0, 0, ny)},
+		{Day, nil, timi.Date(2024, time.May, 15, 0, 0, 0, 0, time.UTC)},
+		{Week, ny, timi.Date(2024, time.May, 13, 0, 0, 0, 0, ny)},
+		{Month, ny, timi.Date(2024, time.May, 1, 0, 0, 0, 0, ny)},
+		{Quarter, ny, timi.Date(2024, time.April, 1, 0, 0, 0, 0, ny)},
+		{Year, ny, timi.Date(2024, time.January, 1, 0, 0, 0, 0, ny)},
+	}
+	for _, tc := range testCases {
+		got, err := TruncateIn(ti, tc.unit, tc.loc)
+		if err != nil {
+			t.Fatalf("TruncateIn %s failed: %v", tc.unit, err)
+		}
+		if !got.Equal(tc.expected) {
+			t.Fatalf("TruncateIn %s in %v: expected %v, got %v", tc.unit, tc.loc, tc.expected, got)
+		}
+		if got.Time.Location() != time.UTC {
+			t.Fatalf("Expected UTC, got %v", got.Time.Location())
+		}
+	}
+
+	// Sunday belongs to the week starting on the previous Monday
+	sunday := timi.Date(2024, time.May, 19, 12, 0, 0, 0, time.UTC)
+	got, err := TruncateIn(sunday, Week, nil)
+	if err != nil || !got.Equal(timi.Date(2024, time.May, 13, 0, 0, 0, 0, time.UTC)) {
+		t.Fatalf("Expected Monday 2024-05-13, got %v, %v", got, err)
+	}
+
+	if got, err := TruncateIn(timi.NilTime, Day, ny); err != nil || got.Valid {
+		t.Fatalf("Expected null, got %v, %v", got, err)
+	}
+	if _, err := TruncateIn(ti, Unit("fortnight"), ny); err == nil {
+		t.Fatalf("Expected an error for an unsupported unit")
+	}
+}
+
+func TestDateBoundaries(t *testing.T) {
+	ny := mustLoadLocation(t, "America/New_York")
+
+	// Days across the start of daylight saving time stay at local midnight
+	from := timi.Date(2024, time.March, 9, 12, 0, 0, 0, ny)
+	to := timi.Date(2024, time.March, 11, 0, 0, 0, 0, ny)
+	got, err := DateBoundaries(from, to, Day, ny)
+	if err != nil {
+		t.Fatalf("DateBoundaries failed: %v", err)
+	}
+	expected := []timi.Time{
+		timi.Date(2024, time.March, 9, 0, 0, 0, 0, ny),
+		timi.Date(2024, time.March, 10, 0, 0, 0, 0, ny),
+		timi.Date(2024, time.March, 11, 0, 0, 0, 0, ny),
+	}
+	if len(got) != len(expected) {
+		t.Fatalf("Expected %v, got %v", expected, got)
+	}
+	for i := range expected {
+		if !got[i].Equal(expected[i]) {
+			t.Fatalf("Boundary %d: expected %v, got %v", i, expected[i], got[i])
+		}
+	}
+	if d := got[2].Sub(got[1]); d != 23*time.Hour {
+		t.Fatalf("Expected a 23 hour day, got %v", d)
+	}
+
+	// The last boundary closes the bucket holding to
+	got, err = DateBoundaries(timi.Date(2024, time.January, 15, 0, 0, 0, 0, time.UTC), timi.Date(2024, time.August, 1, 0, 0, 1, 0, time.UTC), Quarter, nil)
+	if err != nil {
+		t.Fatalf("DateBoundaries failed: %v", err)
+	}
+	expected = []timi.Time{
+		timi.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC),
+		timi.Date(2024, time.April, 1, 0, 0, 0, 0, time.UTC),
+		timi.Date(2024, time.July, 1, 0, 0, 0, 0, time.UTC),
+		timi.Date(2024, time.October, 1, 0, 0, 0, 0, time.UTC),
+	}
+	if len(got) != len(expected) {
+		t.Fatalf("Expected %v, got %v", expected, got)
+	}
+	for i := range expected {
+		if !got[i].Equal(expected[i]) {
+			t.Fatalf("Boundary %d: expected %v, got %v", i, expected[i], got[i])
+		}
+	}
+
+	for _, bounds := range [][2]timi.Time{{to, to}, {to, from}} {
+		if got, err := DateBoundaries(bounds[0], bounds[1], Day, ny); err == nil {
+			t.Fatalf("Expected an error for %v to %v, got %v", bounds[0], bounds[1], got)
+		}
+	}
+	if _, err := DateBoundaries(timi.NilTime, to, Day, ny); err == nil {
+		t.Fatalf("Expected an error for a null bound")
+	}
+}
+
+func TestAggregationStages(t *testing.T) {
+	ny := mustLoadLocation(t, "America/New_York")
+	from := timi.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC)
+	to := timi.Date(2024, time.February, 1, 0, 0, 0, 0, time.UTC)
+	count := bson.D{{Key: "count", Value: bson.D{{Key: "$sum", Value: 1}}}}
+	must := func(d bson.D, err error) bson.D {
+		if err != nil {
+			t.Fatalf("Stage failed: %v", err)
+		}
+		return d
+	}
+
+	testCases := []struct {
+		name     string
+		stage    bson.D
+		expected string
+	}{
+		{
+			"DateTrunc",
+			must(DateTrunc("$created_at", Day, ny)),
+			`{"$dateTrunc":{"date":"$created_at","unit":"day","timezone":"America/New_York"}}`,
+		},
+		{
+			"DateTruncWeek",
+			must(DateTrunc(from, Week, nil)),
+			`{"$dateTrunc":{"date":{"$date":"2024-01-01T00:00:00Z"},"unit":"week","timezone":"UTC","startOfWeek":"monday"}}`,
+		},
+		{
+			"DateDiff",
+			must(DateDiff(from, "$created_at", Month, ny)),
+			`{"$dateDiff":{"startDate":{"$date":"2024-01-01T00:00:00Z"},"endDate":"$created_at","unit":"month","timezone":"America/New_York"}}`,
+		},
+		{
+			"MatchBetween",
+			MatchBetween("created_at", from, to),
+			`{"$match":{"created_at":{"$gte":{"$date":"2024-01-01T00:00:00Z"},"$lt":{"$date":"2024-02-01T00:00:00Z"}}}}`,
+		},
+		{
+			"GroupByDate",
+			must(GroupByDate("created_at", Hour, nil, count)),
+			`{"$group":{"_id":{"$dateTrunc":{"date":"$created_at","unit":"hour","timezone":"UTC"}},"count":{"$sum":1}}}`,
+		},
+		{
+			"BucketByDate",
+			BucketByDate("created_at", []timi.Time{from, to}, nil),
+			`{"$bucket":{"groupBy":"$created_at","boundaries":[{"$date":"2024-01-01T00:00:00Z"},{"$date":"2024-02-01T00:00:00Z"}],"default":null}}`,
+		},
+		{
+			"BucketByDateOutput",
+			BucketByDate("created_at", []timi.Time{from, to}, count),
+			`{"$bucket":{"groupBy":"$created_at","boundaries":[{"$date":"2024-01-01T00:00:00Z"},{"$date":"2024-02-01T00:00:00Z"}],"default":null,"output":{"count":{"$sum":1}}}}`,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := bson.MarshalExtJSON(tc.stage, false, false)
+			if err != nil {
+				t.Fatalf("MarshalExtJSON failed: %v", err)
+			}
+			if string(got) != tc.expected {
+				t.Fatalf("Expected %s, got %s", tc.expected, got)
+			}
+		})
+	}
+}
+
+func TestDecodeBucketKey(t *testing.T) {
+	expected := timi.Date(2024, time.January, 1, 5, 0, 0, 0, time.UTC)
+	raw, err := bson.Marshal(bson.D{
+		{Key: "date", Value: bson.NewDateTimeFromTime(expected.Time)},
+		{Key: "null", Value: nil},
+		{Key: "other", Value: "other"},
+	})
+	if err != nil {
+		t.Fatalf("Marshal failed: %v", err)
+	}
+	doc := bson.Raw(raw)
+
+	got, err := DecodeBucketKey(doc.Lookup("date"))
+	if err != nil || !got.Equal(expected) {
+		t.Fatalf("Expected %v, got %v, %v", expected, got, err)
+	}
+	got, err = DecodeBucketKey(doc.Lookup("null"))
+	if err != nil || got.Valid {
+		t.Fatalf("Expected null, got %v, %v", got, err)
+	}
+	if _, err = DecodeBucketKey(doc.Lookup("other")); err == nil {
+		t.Fatalf("Expected an error for a string key")
+	}
+}
+
+func TestZoneName(t *testing.T) {
+	testCases := []struct {
+		name     string
+		loc      *time.Location
+		expected string
+	}{
+		{"Nil", nil, "UTC"},
+		{"UTC", time.UTC, "UTC"},
+		{"IANA", mustLoadLocation(t, "America/New_York"), "America/New_York"},
+		{"QuarterHour", mustLoadLocation(t, "Asia/Kathmandu"), "Asia/Kathmandu"},
+		{"FixedZone", time.FixedZone("JST", 9*3600), "+09:00"},
+		{"FixedZoneWithIANAName", time.FixedZone("CET", 3600), "+01:00"},
+		{"NegativeOffset", time.FixedZone("", -(3*3600 + 30*60)), "-03:30"},
+		{"UnnamedUTC", time.FixedZone("", 0), "+00:00"},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := zoneName(tc.loc)
+			if err != nil || got != tc.expected {
+				t.Fatalf("Expected %q, got %q, %v", tc.expected, got, err)
+			}
+		})
+	}
+
+	if _, err := DateTrunc("$created_at", Day, time.Local); err == nil {
+		t.Fatalf("Expected an error for time.Local")
+	}
+	if _, err := GroupByDate("created_at", Day, time.Local, nil); err == nil {
+		t.Fatalf("Expected an error for time.Local")
+	}
+}
@@ -15,21 +15,36 @@ func MarshalTimiBSON(t timi.Time) (bson.Type, []byte, error) {
 	if !t.Valid {
 		return bson.TypeNull, nil, nil
 	}
-	return bson.MarshalValue(t.Time)
+	return bson.MarshalValue(dateTime(t))
 }
 
 // UnmarshalTimiBSON unmarshals BSON data to a timi.Time
+// It decodes what the driver decodes into time.Time, with null as NilTime;
+// use UnmarshalTimiBSONWith to choose how legacy forms, such as undefined or
+// Int64 epochs in other units, are read.
 func UnmarshalTimiBSON(bType bson.Type, data []byte) (timi.Time, error) {
 	if bType == bson.TypeNull {
 		return timi.NilTime, nil
 	}
+	if bType == bson.TypeDateTime {
+		if dt, ok := (bson.RawValue{Type: bType, Value: data}).DateTimeOK(); ok {
+			return timeFromDateTime(dt), nil
+		}
+	}
 	var tv time.Time
 	if err := bson.UnmarshalValue(bType, data, &tv); err != nil {
-		return timi.NilTime, err
+		return timi.NilTime, &DecodeError{Type: bType, Err: err}
 	}
 	return timi.Time{Time: tv.UTC(), Valid: true}, nil
 }
 
+// UnmarshalTimiBSONWith unmarshals BSON data to a timi.Time with dec, such
+// as LenientDecoder() for ISO strings, Int64 epochs, Timestamps and
+// undefined.
+func UnmarshalTimiBSONWith(dec Decoder, bType bson.Type, data []byte) (timi.Time, error) {
+	return dec.Unmarshal(bType, data)
+}
+
 // TimiBSONWrapper provides a wrapper type that implements BSON marshaling
 type TimiBSONWrapper struct {
 	timi.Time
@@ -47,3 +62,22 @@ func (t *TimiBSONWrapper) UnmarshalBSONValue(bType bson.Type, data []byte) error
 	t.Time = timiTime
 	return nil
 }
+
+// LenientBSONWrapper is a TimiBSONWrapper that unmarshals with
+// LenientDecoder(), for fields of legacy collections.
+type LenientBSONWrapper struct {
+	timi.Time
+}
+
+func (t LenientBSONWrapper) MarshalBSONValue() (bson.Type, []byte, error) {
+	return MarshalTimiBSON(t.Time)
+}
+
+func (t *LenientBSONWrapper) UnmarshalBSONValue(bType bson.Type, data []byte) error {
+	timiTime, err := UnmarshalTimiBSONWith(LenientDecoder(), bType, data)
+	if err != nil {
+		return err
+	}
+	t.Time = timiTime
+	return nil
+}
@@ -95,3 +95,44 @@ func TestBSONHelpers(t *testing.T) {
 		t.Fatalf("Wrapper with null time should be null after round trip")
 	}
 }
+
+func TestUnmarshalTimiBSONWith(t *testing.T) {
+	expected := timi.Date(2024, 1, 15, 10, 30, 45, 0, time.UTC)
+	bType, data, err := bson.MarshalValue("2024-01-15T10:30:45Z")
+	if err != nil {
+		t.Fatalf("MarshalValue failed: %v", err)
+	}
+	got, err := UnmarshalTimiBSONWith(LenientDecoder(), bType, data)
+	if err != nil || !got.Equal(expected) {
+		t.Fatalf("Expected %v, got %v, %v", expected, got, err)
+	}
+
+	var wrapper LenientBSONWrapper
+	if err = wrapper.UnmarshalBSONValue(bType, data); err != nil || !wrapper.Time.Equal(expected) {
+		t.Fatalf("Expected %v, got %v, %v", expected, wrapper.Time, err)
+	}
+	if err = wrapper.UnmarshalBSONValue(bson.TypeUndefined, nil); err != nil || !wrapper.Time.IsNull() {
+		t.Fatalf("Expected undefined to unmarshal as null, got %v, %v", wrapper.Time, err)
+	}
+	if got, err = UnmarshalTimiBSONWith(StrictDecoder(), bson.TypeUndefined, nil); err == nil {
+		t.Fatalf("Expected the strict decoder to reject undefined, got %v", got)
+	}
+
+	// Int64 epochs in the unit of the decoder
+	dec := LenientDecoder()
+	dec.EpochUnit = time.Second
+	bType, data, err = bson.MarshalValue(expected.Unix())
+	if err != nil {
+		t.Fatalf("MarshalValue failed: %v", err)
+	}
+	if got, err = UnmarshalTimiBSONWith(dec, bType, data); err != nil || !got.Equal(expected) {
+		t.Fatalf("Expected %v, got %v, %v", expected, got, err)
+	}
+
+	// Decoders are values; changing one does not affect the others
+	dec = StrictDecoder()
+	dec.Lenient = true
+	if StrictDecoder().Lenient {
+		t.Fatalf("Expected StrictDecoder to return a fresh strict decoder")
+	}
+}
@@ -0,0 +1,108 @@
+package mongodb
+
+import (
+	"math"
+	"reflect"
+
+	"github.com/ieshan/timi"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+// BSON Codec for timi.Time
+// Registering the codec lets plain timi.Time fields be stored as BSON Date,
+// or null for timi.NilTime, without wrapping them in TimiBSONWrapper.
+// Without it the driver stores the struct as a {time, valid} subdocument.
+// timi.Optional fields are stored like timi.Time; a missing field decodes as
+// Unset and null as Null. Tag them omitempty so Unset fields are left out.
+//
+// timi.Infinity and timi.NegInfinity are stored as the largest and smallest
+// BSON Date, which sort after and before every other date and decode back to
+// the sentinels. Filters and aggregation arguments use the same dates.
+
+var (
+	tTimiTime     = reflect.TypeOf(timi.Time{})
+	tTimiOptional = reflect.TypeOf(timi.Optional{})
+)
+
+// Register installs the timi.Time encoder and the StrictDecoder() on reg.
+// Use Decoder.Register to install a different decoder, such as
+// LenientDecoder().
+func Register(reg *bson.Registry) {
+	StrictDecoder().Register(reg)
+}
+
+// NewRegistry returns the driver's default registry with the timi.Time codec
+// installed. Pass it to options.Client().SetRegistry.
+func NewRegistry() *bson.Registry {
+	reg := bson.NewRegistry()
+	Register(reg)
+	return reg
+}
+
+func encodeTimiValue(_ bson.EncodeContext, vw bson.ValueWriter, val reflect.Value) error {
+	if !val.IsValid() || val.Type() != tTimiTime {
+		return bson.ValueEncoderError{Name: "TimiTimeEncodeValue", Types: []reflect.Type{tTimiTime}, Received: val}
+	}
+	t := val.Interface().(timi.Time)
+	if !t.Valid {
+		return vw.WriteNull()
+	}
+	return vw.WriteDateTime(int64(dateTime(t)))
+}
+
+// dateTime returns the BSON Date of a valid t, clamping Infinity and
+// NegInfinity to the largest and smallest Date.
+func dateTime(t timi.Time) bson.DateTime {
+	switch {
+	case t.IsInfinity():
+		return bson.DateTime(math.MaxInt64)
+	case t.IsNegInfinity():
+		return bson.DateTime(math.MinInt64)
+	}
+	return bson.NewDateTimeFromTime(t.Time)
+}
+
+// timeFromDateTime returns the timi.Time of the BSON Date dt, reading the
+// largest and smallest Date as Infinity and NegInfinity.
+func timeFromDateTime(dt int64) timi.Time {
+	switch dt {
+	case math.MaxInt64:
+		return timi.Infinity
+	case math.MinInt64:
+		return timi.NegInfinity
+	}
+	return timi.Time{Time: bson.DateTime(dt).Time().UTC(), Valid: true}
+}
+
+// encodeOptionalValue writes the time of a timi.Optional with the timi.Time
+// encoder of the registry, so it follows RegisterPrecise. Unset is written as
+// null unless the field is omitted with omitempty.
+func encodeOptionalValue(ec bson.EncodeContext, vw bson.ValueWriter, val reflect.Value) error {
+	if !val.IsValid() || val.Type() != tTimiOptional {
+		return bson.ValueEncoderError{Name: "TimiOptionalEncodeValue", Types: []reflect.Type{tTimiOptional}, Received: val}
+	}
+	enc, err := ec.LookupEncoder(tTimiTime)
+	if err != nil {
+		return err
+	}
+	return enc.EncodeValue(ec, vw, val.FieldByName("Time"))
+}
+
+// decodeOptionalValue reads a present field into a timi.Optional with the
+// timi.Time decoder of the registry. The struct codec does not call it for
+// missing fields, which stay Unset.
+func decodeOptionalValue(dc bson.DecodeContext, vr bson.ValueReader, val reflect.Value) error {
+	if !val.CanSet() || val.Type() != tTimiOptional {
+		return bson.ValueDecoderError{Name: "TimiOptionalDecodeValue", Types: []reflect.Type{tTimiOptional}, Received: val}
+	}
+	dec, err := dc.LookupDecoder(tTimiTime)
+	if err != nil {
+		return err
+	}
+	var t timi.Time
+	if err = dec.DecodeValue(dc, vr, reflect.ValueOf(&t).Elem()); err != nil {
+		return err
+	}
+	val.Set(reflect.ValueOf(timi.OptionalOf(t)))
+	return nil
+}
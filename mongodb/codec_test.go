@@ -0,0 +1,251 @@
+package mongodb
+
+import (
+	"bytes"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/ieshan/timi"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+type codecTestDoc struct {
+	TimeField timi.Time  `bson:"time_field"`
+	NullTime  timi.Time  `bson:"null_time"`
+	Pointer   *timi.Time `bson:"pointer"`
+}
+
+func marshalWithRegistry(t *testing.T, v any) bson.Raw {
+	t.Helper()
+	buf := new(bytes.Buffer)
+	enc := bson.NewEncoder(bson.NewDocumentWriter(buf))
+	enc.SetRegistry(NewRegistry())
+	if err := enc.Encode(v); err != nil {
+		t.Fatalf("Encode failed: %v", err)
+	}
+	return buf.Bytes()
+}
+
+func unmarshalWithRegistry(t *testing.T, data []byte, v any) error {
+	t.Helper()
+	dec := bson.NewDecoder(bson.NewDocumentReader(bytes.NewReader(data)))
+	dec.SetRegistry(NewRegistry())
+	return dec.Decode(v)
+}
+
+func TestRegistry_Encode(t *testing.T) {
+	ti := timi.Date(2024, time.January, 15, 10, 30, 45, 123000000, time.UTC)
+	raw := marshalWithRegistry(t, codecTestDoc{TimeField: ti, NullTime: timi.NilTime, Pointer: &ti})
+
+	for _, key := range []string{"time_field", "pointer"} {
+		v := raw.Lookup(key)
+		if v.Type != bson.TypeDateTime {
+			t.Fatalf("Expected %s to be a DateTime, got %v", key, v.Type)
+		}
+		if got := v.Time().UTC(); !got.Equal(ti.Time) {
+			t.Fatalf("Expected %v, got %v", ti.Time, got)
+		}
+	}
+	if v := raw.Lookup("null_time"); v.Type != bson.TypeNull {
+		t.Fatalf("Expected null_time to be null, got %v", v.Type)
+	}
+}
+
+func TestRegistry_RoundTrip(t *testing.T) {
+	ti := timi.Date(2024, time.January, 15, 10, 30, 45, 123000000, time.FixedZone("EST", -5*3600))
+	raw := marshalWithRegistry(t, codecTestDoc{TimeField: ti, NullTime: timi.NilTime})
+
+	var got codecTestDoc
+	if err := unmarshalWithRegistry(t, raw, &got); err != nil {
+		t.Fatalf("Decode failed: %v", err)
+	}
+	if !got.TimeField.Valid || !got.TimeField.Equal(ti) {
+		t.Fatalf("Expected %v, got %v", ti, got.TimeField)
+	}
+	if got.TimeField.Time.Location() != time.UTC {
+		t.Fatalf("Expected UTC, got %v", got.TimeField.Time.Location())
+	}
+	if !got.NullTime.IsNull() {
+		t.Fatalf("Expected null, got %v", got.NullTime)
+	}
+	if got.Pointer != nil {
+		t.Fatalf("Expected nil pointer, got %v", got.Pointer)
+	}
+}
+
+func TestRegistry_Infinity(t *testing.T) {
+	testCases := []struct {
+		time timi.Time
+		date int64
+	}{
+		{timi.Infinity, math.MaxInt64},
+		{timi.NegInfinity, math.MinInt64},
+	}
+	for _, tc := range testCases {
+		// The codec stores the largest or smallest Date and decodes it back
+		raw := marshalWithRegistry(t, codecTestDoc{TimeField: tc.time})
+		if dt, ok := raw.Lookup("time_field").DateTimeOK(); !ok || dt != tc.date {
+			t.Fatalf("Expected the Date %d for %v, got %v", tc.date, tc.time, raw.Lookup("time_field"))
+		}
+		var got codecTestDoc
+		if err := unmarshalWithRegistry(t, raw, &got); err != nil || got.TimeField != tc.time {
+			t.Fatalf("Expected %v, got %v, %v", tc.time, got.TimeField, err)
+		}
+
+		// So do the helpers and the precise encoding
+		bType, data, err := MarshalTimiBSON(tc.time)
+		if err != nil || bType != bson.TypeDateTime {
+			t.Fatalf("MarshalTimiBSON failed: %v, %v", bType, err)
+		}
+		if dt, ok := (bson.RawValue{Type: bType, Value: data}).DateTimeOK(); !ok || dt != tc.date {
+			t.Fatalf("Expected the Date %d for %v, got %d", tc.date, tc.time, dt)
+		}
+		if back, err := UnmarshalTimiBSON(bType, data); err != nil || back != tc.time {
+			t.Fatalf("Expected %v, got %v, %v", tc.time, back, err)
+		}
+		bType, data, err = MarshalPreciseBSON(tc.time)
+		if err != nil {
+			t.Fatalf("MarshalPreciseBSON failed: %v", err)
+		}
+		if dt, ok := bson.Raw(data).Lookup(PreciseDateField).DateTimeOK(); !ok || dt != tc.date {
+			t.Fatalf("Expected the precise Date %d for %v, got %v", tc.date, tc.time, bson.Raw(data))
+		}
+		if back, err := StrictDecoder().Unmarshal(bType, data); err != nil || back != tc.time {
+			t.Fatalf("Expected %v, got %v, %v", tc.time, back, err)
+		}
+
+		// Aggregation arguments use the same Date
+		if got := dateExpr(tc.time); got != bson.DateTime(tc.date) {
+			t.Fatalf("Expected the Date %d for %v, got %v", tc.date, tc.time, got)
+		}
+	}
+}
+
+func TestRegistry_DecodeLegacyDocument(t *testing.T) {
+	ti := timi.Date(2024, time.January, 15, 10, 30, 45, 123000000, time.UTC)
+	// bson.Marshal uses the default registry, which writes {time, valid} subdocuments
+	raw, err := bson.Marshal(codecTestDoc{TimeField: ti, NullTime: timi.NilTime})
+	if err != nil {
+		t.Fatalf("Marshal failed: %v", err)
+	}
+	if v := bson.Raw(raw).Lookup("time_field"); v.Type != bson.TypeEmbeddedDocument {
+		t.Fatalf("Expected legacy subdocument, got %v", v.Type)
+	}
+
+	var got codecTestDoc
+	if err := unmarshalWithRegistry(t, raw, &got); err != nil {
+		t.Fatalf("Decode failed: %v", err)
+	}
+	if !got.TimeField.Equal(ti) || got.TimeField.IsNull() {
+		t.Fatalf("Expected %v, got %v", ti, got.TimeField)
+	}
+	if !got.NullTime.IsNull() {
+		t.Fatalf("Expected null, got %v", got.NullTime)
+	}
+}
+
+func TestRegistry_DecodeUnsupportedType(t *testing.T) {
+	raw, err := bson.Marshal(bson.D{{Key: "time_field", Value: true}})
+	if err != nil {
+		t.Fatalf("Marshal failed: %v", err)
+	}
+	var got codecTestDoc
+	if err := unmarshalWithRegistry(t, raw, &got); err == nil {
+		t.Fatalf("Expected an error decoding a boolean")
+	}
+}
+
+func TestRegistry_Optional(t *testing.T) {
+	type patchDoc struct {
+		StartTime timi.Optional `bson:"start_time,omitempty"`
+		EndTime   timi.Optional `bson:"end_time,omitempty"`
+		DeletedAt timi.Optional `bson:"deleted_at,omitempty"`
+	}
+	ti := timi.Date(2024, 1, 15, 10, 30, 0, 0, time.UTC)
+	raw := marshalWithRegistry(t, patchDoc{StartTime: timi.OptionalOf(ti), EndTime: timi.OptionalNull()})
+
+	if got := raw.Lookup("start_time").Type; got != bson.TypeDateTime {
+		t.Fatalf("Expected start_time as %v, got %v", bson.TypeDateTime, got)
+	}
+	if got := raw.Lookup("end_time").Type; got != bson.TypeNull {
+		t.Fatalf("Expected end_time as %v, got %v", bson.TypeNull, got)
+	}
+	if _, err := raw.LookupErr("deleted_at"); err == nil {
+		t.Fatalf("Expected deleted_at to be omitted, got %v", raw)
+	}
+
+	var decoded patchDoc
+	if err := unmarshalWithRegistry(t, raw, &decoded); err != nil {
+		t.Fatalf("Decode failed: %v", err)
+	}
+	if !decoded.StartTime.IsSet() || !decoded.StartTime.Time.Equal(ti) {
+		t.Fatalf("Expected start_time %v, got %+v", ti, decoded.StartTime)
+	}
+	if !decoded.EndTime.IsNull() {
+		t.Fatalf("Expected end_time null, got %+v", decoded.EndTime)
+	}
+	if !decoded.DeletedAt.IsUnset() {
+		t.Fatalf("Expected deleted_at unset, got %+v", decoded.DeletedAt)
+	}
+}
+
+func TestRegistryPrecise_Optional(t *testing.T) {
+	type patchDoc struct {
+		StartTime timi.Optional `bson:"start_time,omitempty"`
+	}
+	reg := bson.NewRegistry()
+	RegisterPrecise(reg)
+	ti := timi.Date(2024, 1, 15, 10, 30, 0, 123456789, time.UTC)
+
+	buf := new(bytes.Buffer)
+	enc := bson.NewEncoder(bson.NewDocumentWriter(buf))
+	enc.SetRegistry(reg)
+	if err := enc.Encode(patchDoc{StartTime: timi.OptionalOf(ti)}); err != nil {
+		t.Fatalf("Encode failed: %v", err)
+	}
+	if got := bson.Raw(buf.Bytes()).Lookup("start_time").Type; got != bson.TypeEmbeddedDocument {
+		t.Fatalf("Expected start_time as %v, got %v", bson.TypeEmbeddedDocument, got)
+	}
+
+	var decoded patchDoc
+	dec := bson.NewDecoder(bson.NewDocumentReader(bytes.NewReader(buf.Bytes())))
+	dec.SetRegistry(reg)
+	if err := dec.Decode(&decoded); err != nil {
+		t.Fatalf("Decode failed: %v", err)
+	}
+	if !decoded.StartTime.IsSet() || !decoded.StartTime.Time.Equal(ti) {
+		t.Fatalf("Expected %v, got %+v", ti, decoded.StartTime)
+	}
+}
+
+func TestRegistry_OmitEmpty(t *testing.T) {
+	type omitDoc struct {
+		Zero timi.Time `bson:"zero,omitempty"`
+		Null timi.Time `bson:"null,omitempty"`
+		Kept timi.Time `bson:"kept"`
+	}
+	doc := omitDoc{Zero: timi.Time{Time: time.Time{}, Valid: true}, Null: timi.NilTime, Kept: timi.NilTime}
+	raw := marshalWithRegistry(t, doc)
+
+	if got := raw.Lookup("zero").Type; got != bson.TypeDateTime {
+		t.Fatalf("Expected zero as %v, got %v", bson.TypeDateTime, got)
+	}
+	if _, err := raw.LookupErr("null"); err == nil {
+		t.Fatalf("Expected null to be omitted, got %v", raw)
+	}
+	if got := raw.Lookup("kept").Type; got != bson.TypeNull {
+		t.Fatalf("Expected kept as %v, got %v", bson.TypeNull, got)
+	}
+
+	var decoded omitDoc
+	if err := unmarshalWithRegistry(t, raw, &decoded); err != nil {
+		t.Fatalf("Decode failed: %v", err)
+	}
+	if !decoded.Zero.Valid || !decoded.Zero.Time.IsZero() {
+		t.Fatalf("Expected the valid zero instant, got %v", decoded.Zero)
+	}
+	if decoded.Null.Valid || decoded.Kept.Valid {
+		t.Fatalf("Expected null times, got %v and %v", decoded.Null, decoded.Kept)
+	}
+}
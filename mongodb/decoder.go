@@ -0,0 +1,232 @@
+package mongodb
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"time"
+
+	"github.com/ieshan/timi"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+// Configurable BSON Decoding for timi.Time
+// A Decoder decides which BSON types are accepted and which ones map to
+// timi.NilTime. The lenient mode reads the forms found in legacy collections:
+// ISO 8601 strings, Int64 epochs, BSON Timestamps and the deprecated
+// undefined type.
+
+// Decoder decodes BSON values into timi.Time.
+type Decoder struct {
+	// Lenient accepts ISO 8601 strings, Int64 epochs and BSON Timestamps in
+	// addition to BSON Date. Timestamps keep their seconds and drop the
+	// increment.
+	Lenient bool
+	// EpochUnit is the unit of Int64 epochs in lenient mode: time.Second,
+	// time.Millisecond, time.Microsecond or time.Nanosecond. Zero means
+	// milliseconds, as written by NumberLong(Date.now()).
+	EpochUnit time.Duration
+	// NullAsNil maps BSON null to timi.NilTime. Otherwise null is an error.
+	NullAsNil bool
+	// UndefinedAsNil maps the deprecated BSON undefined type to timi.NilTime.
+	// Otherwise undefined is an error.
+	UndefinedAsNil bool
+	// MissingAsNil maps a missing value to timi.NilTime. Otherwise a missing
+	// value is an error. Struct decoding never calls the decoder for missing
+	// fields, which keep their zero value, timi.NilTime.
+	MissingAsNil bool
+}
+
+// StrictDecoder returns the Decoder used by Register, which accepts only
+// BSON Date, null, the precise {date, ns} subdocument and legacy
+// {time, valid} subdocuments.
+func StrictDecoder() Decoder {
+	return Decoder{NullAsNil: true}
+}
+
+// LenientDecoder returns a Decoder that accepts every supported form and
+// maps null, undefined and missing values to timi.NilTime.
+func LenientDecoder() Decoder {
+	return Decoder{Lenient: true, NullAsNil: true, UndefinedAsNil: true, MissingAsNil: true}
+}
+
+// ErrMissing is returned, wrapped in a DecodeError, for a missing value when
+// MissingAsNil is false.
+var ErrMissing = errors.New("value is missing")
+
+// DecodeError reports the BSON type of a value that could not be decoded.
+type DecodeError struct {
+	Type bson.Type
+	Err  error
+}
+
+func (e *DecodeError) Error() string {
+	if e.Type == 0 {
+		return "timi/mongodb: cannot decode into a timi.Time: " + e.Err.Error()
+	}
+	return fmt.Sprintf("timi/mongodb: cannot decode BSON %v into a timi.Time: %v", e.Type, e.Err)
+}
+
+func (e *DecodeError) Unwrap() error {
+	return e.Err
+}
+
+var tRawValue = reflect.TypeOf(bson.RawValue{})
+
+// isoLayouts are tried in order for BSON strings in lenient mode. Layouts
+// without an offset are read as UTC.
+var isoLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02T15:04:05.999999999Z0700",
+	"2006-01-02T15:04:05.999999999",
+	"2006-01-02 15:04:05.999999999Z07:00",
+	"2006-01-02 15:04:05.999999999",
+	time.DateOnly,
+}
+
+// Unmarshal decodes the BSON value data of type bType. A zero bType, as
+// found in the RawValue returned by bson.Raw.Lookup for a missing key, is a
+// missing value.
+func (d Decoder) Unmarshal(bType bson.Type, data []byte) (timi.Time, error) {
+	return d.decode(bson.RawValue{Type: bType, Value: data})
+}
+
+// Lookup decodes the value at the given path of doc. A key that is not
+// found is a missing value.
+func (d Decoder) Lookup(doc bson.Raw, key ...string) (timi.Time, error) {
+	return d.decode(doc.Lookup(key...))
+}
+
+// Register installs the timi.Time encoder and this decoder on reg, along
+// with the timi.Optional codec.
+func (d Decoder) Register(reg *bson.Registry) {
+	reg.RegisterTypeEncoder(tTimiTime, bson.ValueEncoderFunc(encodeTimiValue))
+	reg.RegisterTypeDecoder(tTimiTime, bson.ValueDecoderFunc(d.decodeValue))
+	reg.RegisterTypeEncoder(tTimiOptional, bson.ValueEncoderFunc(encodeOptionalValue))
+	reg.RegisterTypeDecoder(tTimiOptional, bson.ValueDecoderFunc(decodeOptionalValue))
+}
+
+func (d Decoder) decodeValue(dc bson.DecodeContext, vr bson.ValueReader, val reflect.Value) error {
+	if !val.CanSet() || val.Type() != tTimiTime {
+		return bson.ValueDecoderError{Name: "TimiTimeDecodeValue", Types: []reflect.Type{tTimiTime}, Received: val}
+	}
+	rawDec, err := dc.LookupDecoder(tRawValue)
+	if err != nil {
+		return err
+	}
+	var rv bson.RawValue
+	if err = rawDec.DecodeValue(dc, vr, reflect.ValueOf(&rv).Elem()); err != nil {
+		return err
+	}
+	t, err := d.decode(rv)
+	if err != nil {
+		return err
+	}
+	val.Set(reflect.ValueOf(t))
+	return nil
+}
+
+func (d Decoder) decode(rv bson.RawValue) (timi.Time, error) {
+	switch rv.Type {
+	case 0:
+		if d.MissingAsNil {
+			return timi.NilTime, nil
+		}
+		return timi.NilTime, &DecodeError{Err: ErrMissing}
+	case bson.TypeNull:
+		if d.NullAsNil {
+			return timi.NilTime, nil
+		}
+		return timi.NilTime, &DecodeError{Type: rv.Type, Err: errors.New("null is not allowed")}
+	case bson.TypeUndefined:
+		if d.UndefinedAsNil {
+			return timi.NilTime, nil
+		}
+		return timi.NilTime, &DecodeError{Type: rv.Type, Err: errors.New("undefined is not allowed")}
+	case bson.TypeDateTime:
+		dt, ok := rv.DateTimeOK()
+		if !ok {
+			return timi.NilTime, &DecodeError{Type: rv.Type, Err: errors.New("malformed value")}
+		}
+		return timeFromDateTime(dt), nil
+	case bson.TypeEmbeddedDocument:
+		doc, ok := rv.DocumentOK()
+		if !ok {
+			return timi.NilTime, &DecodeError{Type: rv.Type, Err: errors.New("malformed value")}
+		}
+		if _, err := doc.LookupErr(PreciseDateField); err == nil {
+			return decodePreciseDocument(doc)
+		}
+		return decodeLegacyDocument(doc)
+	}
+	if !d.Lenient {
+		return timi.NilTime, &DecodeError{Type: rv.Type, Err: errors.New("unsupported type")}
+	}
+
+	var tv time.Time
+	switch rv.Type {
+	case bson.TypeString:
+		s, ok := rv.StringValueOK()
+		if !ok {
+			return timi.NilTime, &DecodeError{Type: rv.Type, Err: errors.New("malformed value")}
+		}
+		parsed, err := parseISO(s)
+		if err != nil {
+			return timi.NilTime, &DecodeError{Type: rv.Type, Err: err}
+		}
+		tv = parsed
+	case bson.TypeInt64:
+		v, ok := rv.Int64OK()
+		if !ok {
+			return timi.NilTime, &DecodeError{Type: rv.Type, Err: errors.New("malformed value")}
+		}
+		switch d.EpochUnit {
+		case time.Second:
+			tv = time.Unix(v, 0)
+		case 0, time.Millisecond:
+			tv = time.UnixMilli(v)
+		case time.Microsecond:
+			tv = time.UnixMicro(v)
+		case time.Nanosecond:
+			tv = time.Unix(0, v)
+		default:
+			return timi.NilTime, &DecodeError{Type: rv.Type, Err: fmt.Errorf("unsupported epoch unit %v", d.EpochUnit)}
+		}
+	case bson.TypeTimestamp:
+		sec, _, ok := rv.TimestampOK()
+		if !ok {
+			return timi.NilTime, &DecodeError{Type: rv.Type, Err: errors.New("malformed value")}
+		}
+		tv = time.Unix(int64(sec), 0)
+	default:
+		return timi.NilTime, &DecodeError{Type: rv.Type, Err: errors.New("unsupported type")}
+	}
+	return timi.Time{Time: tv.UTC(), Valid: true}, nil
+}
+
+func parseISO(s string) (time.Time, error) {
+	for _, layout := range isoLayouts {
+		if tv, err := time.Parse(layout, s); err == nil {
+			return tv, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("cannot parse %q as an ISO 8601 time", s)
+}
+
+// decodeLegacyDocument reads the {time, valid} subdocument written by the
+// driver's default struct codec, so documents stored before the codec was
+// registered can still be read.
+func decodeLegacyDocument(doc bson.Raw) (timi.Time, error) {
+	valid, ok := doc.Lookup("valid").BooleanOK()
+	if !ok {
+		return timi.NilTime, &DecodeError{Type: bson.TypeEmbeddedDocument, Err: errors.New("subdocument has no boolean valid field")}
+	}
+	if !valid {
+		return timi.NilTime, nil
+	}
+	dt, ok := doc.Lookup("time").DateTimeOK()
+	if !ok {
+		return timi.NilTime, &DecodeError{Type: bson.TypeEmbeddedDocument, Err: errors.New("subdocument has no date time field")}
+	}
+	return timeFromDateTime(dt), nil
+}
@@ -0,0 +1,210 @@
+package mongodb
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ieshan/timi"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+func mustMarshalValue(t *testing.T, v any) (bson.Type, []byte) {
+	t.Helper()
+	bType, data, err := bson.MarshalValue(v)
+	if err != nil {
+		t.Fatalf("MarshalValue failed: %v", err)
+	}
+	return bType, data
+}
+
+func TestLenientDecoder_Unmarshal(t *testing.T) {
+	expected := timi.Date(2024, time.January, 15, 10, 30, 45, 0, time.UTC)
+	testCases := []struct {
+		name  string
+		value any
+	}{
+		{"DateTime", bson.NewDateTimeFromTime(expected.Time)},
+		{"RFC3339", "2024-01-15T10:30:45Z"},
+		{"RFC3339Offset", "2024-01-15T16:00:45+05:30"},
+		{"CompactOffset", "2024-01-15T05:30:45-0500"},
+		{"NoZone", "2024-01-15T10:30:45"},
+		{"SpaceSeparator", "2024-01-15 10:30:45"},
+		{"Int64Milliseconds", expected.UnixMilli()},
+		{"Timestamp", bson.Timestamp{T: uint32(expected.Unix()), I: 7}},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			bType, data := mustMarshalValue(t, tc.value)
+			got, err := LenientDecoder().Unmarshal(bType, data)
+			if err != nil {
+				t.Fatalf("Unmarshal failed: %v", err)
+			}
+			if !got.Valid || !got.Equal(expected) {
+				t.Fatalf("Expected %v, got %v", expected, got)
+			}
+			if got.Time.Location() != time.UTC {
+				t.Fatalf("Expected UTC, got %v", got.Time.Location())
+			}
+		})
+	}
+
+	bType, data := mustMarshalValue(t, "2024-01-15")
+	got, err := LenientDecoder().Unmarshal(bType, data)
+	if err != nil {
+		t.Fatalf("Unmarshal date only failed: %v", err)
+	}
+	if !got.Equal(timi.Date(2024, time.January, 15, 0, 0, 0, 0, time.UTC)) {
+		t.Fatalf("Expected midnight, got %v", got)
+	}
+}
+
+func TestDecoder_EpochUnit(t *testing.T) {
+	expected := timi.Date(2024, time.January, 15, 10, 30, 45, 123456000, time.UTC)
+	testCases := []struct {
+		unit  time.Duration
+		value int64
+		want  timi.Time
+	}{
+		{time.Second, expected.Unix(), expected.Truncate(time.Second)},
+		{time.Millisecond, expected.UnixMilli(), expected.Truncate(time.Millisecond)},
+		{time.Microsecond, expected.UnixMicro(), expected},
+		{time.Nanosecond, expected.UnixNano(), expected},
+	}
+	for _, tc := range testCases {
+		d := Decoder{Lenient: true, EpochUnit: tc.unit}
+		bType, data := mustMarshalValue(t, tc.value)
+		got, err := d.Unmarshal(bType, data)
+		if err != nil {
+			t.Fatalf("Unmarshal with unit %v failed: %v", tc.unit, err)
+		}
+		if !got.Equal(tc.want) {
+			t.Fatalf("Unit %v: expected %v, got %v", tc.unit, tc.want, got)
+		}
+	}
+
+	bType, data := mustMarshalValue(t, int64(1))
+	if _, err := (Decoder{Lenient: true, EpochUnit: time.Hour}).Unmarshal(bType, data); err == nil {
+		t.Fatalf("Expected an error for an unsupported epoch unit")
+	}
+}
+
+func TestDecoder_NilMapping(t *testing.T) {
+	nullType, nullData := mustMarshalValue(t, bson.Null{})
+	undefinedType, undefinedData := mustMarshalValue(t, bson.Undefined{})
+
+	for _, d := range []Decoder{LenientDecoder(), {NullAsNil: true, UndefinedAsNil: true, MissingAsNil: true}} {
+		for _, v := range []bson.RawValue{{Type: nullType, Value: nullData}, {Type: undefinedType, Value: undefinedData}, {}} {
+			got, err := d.Unmarshal(v.Type, v.Value)
+			if err != nil {
+				t.Fatalf("Unmarshal %v failed: %v", v.Type, err)
+			}
+			if !got.IsNull() {
+				t.Fatalf("Expected null for %v, got %v", v.Type, got)
+			}
+		}
+	}
+
+	strict := Decoder{Lenient: true}
+	for _, v := range []bson.RawValue{{Type: nullType, Value: nullData}, {Type: undefinedType, Value: undefinedData}} {
+		_, err := strict.Unmarshal(v.Type, v.Value)
+		var decErr *DecodeError
+		if !errors.As(err, &decErr) || decErr.Type != v.Type {
+			t.Fatalf("Expected a DecodeError for %v, got %v", v.Type, err)
+		}
+	}
+	if _, err := strict.Unmarshal(0, nil); !errors.Is(err, ErrMissing) {
+		t.Fatalf("Expected ErrMissing, got %v", err)
+	}
+}
+
+func TestDecoder_Lookup(t *testing.T) {
+	expected := timi.Date(2024, time.January, 15, 10, 30, 45, 0, time.UTC)
+	doc, err := bson.Marshal(bson.D{
+		{Key: "meta", Value: bson.D{{Key: "created_at", Value: "2024-01-15T10:30:45Z"}}},
+	})
+	if err != nil {
+		t.Fatalf("Marshal failed: %v", err)
+	}
+
+	got, err := LenientDecoder().Lookup(doc, "meta", "created_at")
+	if err != nil {
+		t.Fatalf("Lookup failed: %v", err)
+	}
+	if !got.Equal(expected) {
+		t.Fatalf("Expected %v, got %v", expected, got)
+	}
+
+	got, err = LenientDecoder().Lookup(doc, "meta", "updated_at")
+	if err != nil || !got.IsNull() {
+		t.Fatalf("Expected null for a missing key, got %v, %v", got, err)
+	}
+	if _, err = StrictDecoder().Lookup(doc, "meta", "updated_at"); !errors.Is(err, ErrMissing) {
+		t.Fatalf("Expected ErrMissing, got %v", err)
+	}
+}
+
+func TestDecoder_Errors(t *testing.T) {
+	testCases := []struct {
+		name    string
+		decoder Decoder
+		value   any
+		bType   bson.Type
+	}{
+		{"StrictString", StrictDecoder(), "2024-01-15T10:30:45Z", bson.TypeString},
+		{"StrictInt64", StrictDecoder(), int64(0), bson.TypeInt64},
+		{"LenientBadString", LenientDecoder(), "yesterday", bson.TypeString},
+		{"LenientBoolean", LenientDecoder(), true, bson.TypeBoolean},
+		{"LenientInt32", LenientDecoder(), int32(0), bson.TypeInt32},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			bType, data := mustMarshalValue(t, tc.value)
+			_, err := tc.decoder.Unmarshal(bType, data)
+			var decErr *DecodeError
+			if !errors.As(err, &decErr) {
+				t.Fatalf("Expected a DecodeError, got %v", err)
+			}
+			if decErr.Type != tc.bType {
+				t.Fatalf("Expected type %v, got %v", tc.bType, decErr.Type)
+			}
+			if !strings.Contains(err.Error(), tc.bType.String()) {
+				t.Fatalf("Expected the error to name %v, got %q", tc.bType, err)
+			}
+		})
+	}
+}
+
+func TestDecoder_Register(t *testing.T) {
+	raw, err := bson.Marshal(bson.D{
+		{Key: "time_field", Value: "2024-01-15T10:30:45Z"},
+		{Key: "null_time", Value: bson.Undefined{}},
+	})
+	if err != nil {
+		t.Fatalf("Marshal failed: %v", err)
+	}
+
+	// The default codec rejects strings
+	var strict codecTestDoc
+	if err := unmarshalWithRegistry(t, raw, &strict); err == nil {
+		t.Fatalf("Expected an error decoding a string with the strict decoder")
+	}
+
+	reg := bson.NewRegistry()
+	LenientDecoder().Register(reg)
+	dec := bson.NewDecoder(bson.NewDocumentReader(bytes.NewReader(raw)))
+	dec.SetRegistry(reg)
+	var got codecTestDoc
+	if err := dec.Decode(&got); err != nil {
+		t.Fatalf("Decode failed: %v", err)
+	}
+	expected := timi.Date(2024, time.January, 15, 10, 30, 45, 0, time.UTC)
+	if !got.TimeField.Equal(expected) {
+		t.Fatalf("Expected %v, got %v", expected, got.TimeField)
+	}
+	if !got.NullTime.IsNull() {
+		t.Fatalf("Expected null for undefined, got %v", got.NullTime)
+	}
+}
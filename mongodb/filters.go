@@ -0,0 +1,79 @@
+package mongodb
+
+import (
+	"github.com/ieshan/timi"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+// Filter Builders for timi.Time
+// In MongoDB {field: null} also matches documents without the field, so
+// IsNull and IsMissing tell the two apart. Bounds are always encoded as BSON
+// Date, whether or not the codec is registered. Comparison operators only
+// match values of the same type, so range filters never match null or
+// missing fields. A null bound means unbounded on that side.
+
+// Bounds selects which ends of a Between range are included.
+type Bounds int
+
+const (
+	// Closed includes both ends: a <= field <= b.
+	Closed Bounds = iota
+	// ClosedOpen includes the lower end only: a <= field < b.
+	ClosedOpen
+	// OpenClosed includes the upper end only: a < field <= b.
+	OpenClosed
+	// Open excludes both ends: a < field < b.
+	Open
+)
+
+// IsNull matches documents where field is present and null.
+func IsNull(field string) bson.D {
+	return bson.D{{Key: field, Value: bson.D{{Key: "$type", Value: "null"}}}}
+}
+
+// IsMissing matches documents without field.
+func IsMissing(field string) bson.D {
+	return bson.D{{Key: field, Value: bson.D{{Key: "$exists", Value: false}}}}
+}
+
+// NotNull matches documents where field is present and not null.
+func NotNull(field string) bson.D {
+	return bson.D{{Key: field, Value: bson.D{{Key: "$ne", Value: nil}}}}
+}
+
+// Before matches documents where field is before t.
+// A null t matches every non-null field.
+func Before(field string, t timi.Time) bson.D {
+	return Between(field, timi.NilTime, t, Open)
+}
+
+// After matches documents where field is after t.
+// A null t matches every non-null field.
+func After(field string, t timi.Time) bson.D {
+	return Between(field, t, timi.NilTime, Open)
+}
+
+// Between matches documents where field lies between a and b, with the ends
+// included as selected by bounds. A null a or b leaves that side unbounded;
+// with both null, Between matches every non-null field.
+func Between(field string, a, b timi.Time, bounds Bounds) bson.D {
+	var cond bson.D
+	if a.Valid {
+		op := "$gt"
+		if bounds == Closed || bounds == ClosedOpen {
+			op = "$gte"
+		}
+		cond = append(cond, bson.E{Key: op, Value: dateTime(a)})
+	}
+	if b.Valid {
+		op := "$lt"
+		if bounds == Closed || bounds == OpenClosed {
+			op = "$lte"
+		}
+		cond = append(cond, bson.E{Key: op, Value: dateTime(b)})
+	}
+	if cond == nil {
+		return NotNull(field)
+	}
+	return bson.D{{Key: field, Value: cond}}
+}
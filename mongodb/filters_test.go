@@ -0,0 +1,53 @@
+package mongodb
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ieshan/timi"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+func TestFilters(t *testing.T) {
+	a := timi.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC)
+	b := timi.Date(2025, time.January, 1, 0, 0, 0, 0, time.UTC)
+	const (
+		aJSON = `{"$date":"2024-01-01T00:00:00Z"}`
+		bJSON = `{"$date":"2025-01-01T00:00:00Z"}`
+	)
+
+	testCases := []struct {
+		name     string
+		filter   bson.D
+		expected string
+	}{
+		{"IsNull", IsNull("f"), `{"f":{"$type":"null"}}`},
+		{"IsMissing", IsMissing("f"), `{"f":{"$exists":false}}`},
+		{"NotNull", NotNull("f"), `{"f":{"$ne":null}}`},
+		{"Before", Before("f", b), `{"f":{"$lt":` + bJSON + `}}`},
+		{"After", After("f", a), `{"f":{"$gt":` + aJSON + `}}`},
+		{"BeforeNull", Before("f", timi.NilTime), `{"f":{"$ne":null}}`},
+		{"AfterNull", After("f", timi.NilTime), `{"f":{"$ne":null}}`},
+		{"Closed", Between("f", a, b, Closed), `{"f":{"$gte":` + aJSON + `,"$lte":` + bJSON + `}}`},
+		{"ClosedOpen", Between("f", a, b, ClosedOpen), `{"f":{"$gte":` + aJSON + `,"$lt":` + bJSON + `}}`},
+		{"OpenClosed", Between("f", a, b, OpenClosed), `{"f":{"$gt":` + aJSON + `,"$lte":` + bJSON + `}}`},
+		{"Open", Between("f", a, b, Open), `{"f":{"$gt":` + aJSON + `,"$lt":` + bJSON + `}}`},
+		{"UnboundedLower", Between("f", timi.NilTime, b, Closed), `{"f":{"$lte":` + bJSON + `}}`},
+		{"UnboundedUpper", Between("f", a, timi.NilTime, ClosedOpen), `{"f":{"$gte":` + aJSON + `}}`},
+		{"Unbounded", Between("f", timi.NilTime, timi.NilTime, Closed), `{"f":{"$ne":null}}`},
+		// The infinities are the largest and smallest BSON Date
+		{"BeforeInfinity", Before("f", timi.Infinity), `{"f":{"$lt":{"$date":{"$numberLong":"9223372036854775807"}}}}`},
+		{"AfterNegInfinity", After("f", timi.NegInfinity), `{"f":{"$gt":{"$date":{"$numberLong":"-9223372036854775808"}}}}`},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := bson.MarshalExtJSON(tc.filter, false, false)
+			if err != nil {
+				t.Fatalf("MarshalExtJSON failed: %v", err)
+			}
+			if string(got) != tc.expected {
+				t.Fatalf("Expected %s, got %s", tc.expected, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,62 @@
+package mongodb
+
+import (
+	"context"
+	"errors"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+)
+
+// Migration Helpers for timi.Time
+// Documents written without the codec hold {time: Date, valid: bool}
+// subdocuments. These helpers rewrite such fields in place to a BSON Date,
+// or null when valid is false. Only subdocuments with a boolean valid are
+// rewritten; fields holding a Date, null or another subdocument, such as the
+// precise encoding {date, ns}, are left untouched, so the migration can be
+// run more than once.
+
+// LegacyFieldsFilter returns a filter matching documents where any of the
+// given fields is still a legacy subdocument.
+func LegacyFieldsFilter(fields ...string) bson.D {
+	or := make(bson.A, len(fields))
+	for i, field := range fields {
+		or[i] = bson.D{
+			{Key: field, Value: bson.D{{Key: "$type", Value: "object"}}},
+			{Key: field + ".valid", Value: bson.D{{Key: "$type", Value: "bool"}}},
+		}
+	}
+	return bson.D{{Key: "$or", Value: or}}
+}
+
+// LegacyFieldsUpdate returns an update pipeline converting the given fields
+// from legacy subdocuments to BSON Date or null.
+func LegacyFieldsUpdate(fields ...string) mongo.Pipeline {
+	set := make(bson.D, len(fields))
+	for i, field := range fields {
+		ref := "$" + field
+		set[i] = bson.E{Key: field, Value: bson.D{{Key: "$cond", Value: bson.D{
+			{Key: "if", Value: bson.D{{Key: "$eq", Value: bson.A{bson.D{{Key: "$type", Value: ref + ".valid"}}, "bool"}}}},
+			{Key: "then", Value: bson.D{{Key: "$cond", Value: bson.D{
+				{Key: "if", Value: bson.D{{Key: "$eq", Value: bson.A{ref + ".valid", true}}}},
+				{Key: "then", Value: ref + ".time"},
+				{Key: "else", Value: nil},
+			}}}},
+			{Key: "else", Value: ref},
+		}}}}
+	}
+	return mongo.Pipeline{{{Key: "$set", Value: set}}}
+}
+
+// MigrateLegacyFields rewrites the given fields of every matching document
+// in col and returns the number of modified documents.
+func MigrateLegacyFields(ctx context.Context, col *mongo.Collection, fields ...string) (int64, error) {
+	if len(fields) == 0 {
+		return 0, errors.New("timi/mongodb: no fields to migrate")
+	}
+	res, err := col.UpdateMany(ctx, LegacyFieldsFilter(fields...), LegacyFieldsUpdate(fields...))
+	if err != nil {
+		return 0, err
+	}
+	return res.ModifiedCount, nil
+}
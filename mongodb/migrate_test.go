@@ -0,0 +1,65 @@
+package mongodb
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ieshan/timi"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+func TestLegacyFieldsFilter(t *testing.T) {
+	got, err := bson.MarshalExtJSON(LegacyFieldsFilter("created_at", "meta.updated_at"), false, false)
+	if err != nil {
+		t.Fatalf("MarshalExtJSON failed: %v", err)
+	}
+	expected := `{"$or":[{"created_at":{"$type":"object"},"created_at.valid":{"$type":"bool"}},` +
+		`{"meta.updated_at":{"$type":"object"},"meta.updated_at.valid":{"$type":"bool"}}]}`
+	if string(got) != expected {
+		t.Fatalf("Expected %s, got %s", expected, got)
+	}
+}
+
+func TestLegacyFieldsUpdate(t *testing.T) {
+	pipeline := LegacyFieldsUpdate("created_at")
+	if len(pipeline) != 1 {
+		t.Fatalf("Expected 1 stage, got %d", len(pipeline))
+	}
+	got, err := bson.MarshalExtJSON(pipeline[0], false, false)
+	if err != nil {
+		t.Fatalf("MarshalExtJSON failed: %v", err)
+	}
+	expected := `{"$set":{"created_at":{"$cond":{"if":{"$eq":[{"$type":"$created_at.valid"},"bool"]},` +
+		`"then":{"$cond":{"if":{"$eq":["$created_at.valid",true]},"then":"$created_at.time","else":null}},` +
+		`"else":"$created_at"}}}}`
+	if string(got) != expected {
+		t.Fatalf("Expected %s, got %s", expected, got)
+	}
+}
+
+func TestMigrateLegacyFields_NoFields(t *testing.T) {
+	if _, err := MigrateLegacyFields(context.Background(), nil); err == nil {
+		t.Fatalf("Expected an error without fields")
+	}
+}
+
+func TestLegacyFieldsFilter_Precise(t *testing.T) {
+	// The precise encoding is also a subdocument; the filter and update
+	// leave it alone because it has no boolean valid field
+	_, data, err := MarshalPreciseBSON(timi.Date(2024, 1, 15, 12, 0, 0, 123456789, time.UTC))
+	if err != nil {
+		t.Fatalf("MarshalPreciseBSON failed: %v", err)
+	}
+	if _, err = bson.Raw(data).LookupErr("valid"); err == nil {
+		t.Fatalf("Expected no valid field in the precise encoding %v", bson.Raw(data))
+	}
+	filter, err := bson.MarshalExtJSON(LegacyFieldsFilter("created_at"), false, false)
+	if err != nil {
+		t.Fatalf("MarshalExtJSON failed: %v", err)
+	}
+	if !strings.Contains(string(filter), `"created_at.valid":{"$type":"bool"}`) {
+		t.Fatalf("Expected the filter to require a boolean valid, got %s", filter)
+	}
+}
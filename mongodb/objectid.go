@@ -0,0 +1,90 @@
+package mongodb
+
+import (
+	"encoding/binary"
+	"math"
+	"time"
+
+	"github.com/ieshan/timi"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+// ObjectID Helper Functions for timi.Time
+// An ObjectID starts with its creation time as big-endian Unix seconds, so
+// ObjectIDs sort by creation time and the _id index can serve time range
+// queries on collections without a creation time field. The timestamp has
+// second precision and covers 1970 to 2106.
+
+// TimeFromObjectID returns the creation time embedded in id.
+// The zero ObjectID returns timi.NilTime.
+func TimeFromObjectID(id bson.ObjectID) timi.Time {
+	if id.IsZero() {
+		return timi.NilTime
+	}
+	return timi.Time{Time: id.Timestamp().UTC(), Valid: true}
+}
+
+// MinObjectIDAt returns the smallest ObjectID created in the second of t.
+// A null t returns the smallest ObjectID.
+func MinObjectIDAt(t timi.Time) bson.ObjectID {
+	if !t.Valid {
+		return bson.NilObjectID
+	}
+	return objectIDAt(t.Unix(), 0x00)
+}
+
+// MaxObjectIDAt returns the largest ObjectID created in the second of t.
+// A null t returns the largest ObjectID.
+func MaxObjectIDAt(t timi.Time) bson.ObjectID {
+	if !t.Valid {
+		return objectIDAt(math.MaxUint32, 0xff)
+	}
+	return objectIDAt(t.Unix(), 0xff)
+}
+
+// ObjectIDBetween matches documents whose _id was created between a and b,
+// with the ends included as selected by bounds. Creation times are whole
+// seconds, and sub-second bounds are compared against them exactly. A null
+// a or b leaves that side unbounded; with both null, every document matches.
+// Sort on _id to page through the result in creation order.
+func ObjectIDBetween(a, b timi.Time, bounds Bounds) bson.D {
+	var cond bson.D
+	if a.Valid {
+		// t > a for whole seconds t is t >= floor(a)+1
+		secs := a.Unix() + 1
+		if bounds == Closed || bounds == ClosedOpen {
+			secs = ceilUnix(a.Time)
+		}
+		cond = append(cond, bson.E{Key: "$gte", Value: objectIDAt(secs, 0x00)})
+	}
+	if b.Valid {
+		if bounds == Closed || bounds == OpenClosed {
+			cond = append(cond, bson.E{Key: "$lte", Value: objectIDAt(b.Unix(), 0xff)})
+		} else {
+			cond = append(cond, bson.E{Key: "$lt", Value: objectIDAt(ceilUnix(b.Time), 0x00)})
+		}
+	}
+	if cond == nil {
+		return bson.D{}
+	}
+	return bson.D{{Key: "_id", Value: cond}}
+}
+
+func ceilUnix(t time.Time) int64 {
+	if t.Nanosecond() > 0 {
+		return t.Unix() + 1
+	}
+	return t.Unix()
+}
+
+// objectIDAt returns an ObjectID with the timestamp secs, clamped to the
+// range of the field, and the remaining bytes set to fill.
+func objectIDAt(secs int64, fill byte) bson.ObjectID {
+	secs = max(0, min(secs, math.MaxUint32))
+	var id bson.ObjectID
+	binary.BigEndian.PutUint32(id[:4], uint32(secs))
+	for i := 4; i < len(id); i++ {
+		id[i] = fill
+	}
+	return id
+}
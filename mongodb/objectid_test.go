@@ -0,0 +1,102 @@
+package mongodb
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/ieshan/timi"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+func TestTimeFromObjectID(t *testing.T) {
+	id := bson.NewObjectIDFromTimestamp(time.Date(2024, time.January, 15, 10, 30, 45, 0, time.UTC))
+	expected := timi.Date(2024, time.January, 15, 10, 30, 45, 0, time.UTC)
+	got := TimeFromObjectID(id)
+	if !got.Valid || !got.Equal(expected) {
+		t.Fatalf("Expected %v, got %v", expected, got)
+	}
+	if got.Time.Location() != time.UTC {
+		t.Fatalf("Expected UTC, got %v", got.Time.Location())
+	}
+	if TimeFromObjectID(bson.NilObjectID).Valid {
+		t.Fatalf("Expected null for the zero ObjectID")
+	}
+}
+
+func TestMinMaxObjectIDAt(t *testing.T) {
+	ti := timi.Date(2024, time.January, 15, 10, 30, 45, 500000000, time.UTC)
+	minID, maxID := MinObjectIDAt(ti), MaxObjectIDAt(ti)
+
+	if got := TimeFromObjectID(minID); !got.Equal(ti.Truncate(time.Second)) {
+		t.Fatalf("Expected min at %v, got %v", ti.Truncate(time.Second), got)
+	}
+	if got := TimeFromObjectID(maxID); !got.Equal(ti.Truncate(time.Second)) {
+		t.Fatalf("Expected max at %v, got %v", ti.Truncate(time.Second), got)
+	}
+
+	id := bson.NewObjectIDFromTimestamp(ti.Time)
+	if bytes.Compare(minID[:], id[:]) > 0 || bytes.Compare(id[:], maxID[:]) > 0 {
+		t.Fatalf("Expected %v <= %v <= %v", minID, id, maxID)
+	}
+	next := MinObjectIDAt(ti.Add(time.Second))
+	if bytes.Compare(maxID[:], next[:]) >= 0 {
+		t.Fatalf("Expected %v < %v", maxID, next)
+	}
+
+	if MinObjectIDAt(timi.NilTime) != bson.NilObjectID {
+		t.Fatalf("Expected the zero ObjectID for a null time")
+	}
+	if got := MaxObjectIDAt(timi.NilTime).Hex(); got != "ffffffffffffffffffffffff" {
+		t.Fatalf("Expected the largest ObjectID for a null time, got %s", got)
+	}
+
+	// Out of range times are clamped
+	if got := MinObjectIDAt(timi.Date(1900, time.January, 1, 0, 0, 0, 0, time.UTC)); got != bson.NilObjectID {
+		t.Fatalf("Expected the zero ObjectID before 1970, got %v", got)
+	}
+	if got := MaxObjectIDAt(timi.Date(2200, time.January, 1, 0, 0, 0, 0, time.UTC)).Hex(); got != "ffffffffffffffffffffffff" {
+		t.Fatalf("Expected the largest ObjectID after 2106, got %s", got)
+	}
+}
+
+func TestObjectIDBetween(t *testing.T) {
+	a := timi.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC)
+	aFrac := a.Add(500 * time.Millisecond)
+	b := timi.Date(2024, time.January, 2, 0, 0, 0, 0, time.UTC)
+	bFrac := b.Add(500 * time.Millisecond)
+	aHex := MinObjectIDAt(a).Hex()
+	aNextHex := MinObjectIDAt(a.Add(time.Second)).Hex()
+	bMinHex := MinObjectIDAt(b).Hex()
+	bMaxHex := MaxObjectIDAt(b).Hex()
+	bNextHex := MinObjectIDAt(b.Add(time.Second)).Hex()
+	oid := func(hex string) string { return `{"$oid":"` + hex + `"}` }
+
+	testCases := []struct {
+		name     string
+		filter   bson.D
+		expected string
+	}{
+		{"Closed", ObjectIDBetween(a, b, Closed), `{"_id":{"$gte":` + oid(aHex) + `,"$lte":` + oid(bMaxHex) + `}}`},
+		{"ClosedOpen", ObjectIDBetween(a, b, ClosedOpen), `{"_id":{"$gte":` + oid(aHex) + `,"$lt":` + oid(bMinHex) + `}}`},
+		{"OpenClosed", ObjectIDBetween(a, b, OpenClosed), `{"_id":{"$gte":` + oid(aNextHex) + `,"$lte":` + oid(bMaxHex) + `}}`},
+		{"Open", ObjectIDBetween(a, b, Open), `{"_id":{"$gte":` + oid(aNextHex) + `,"$lt":` + oid(bMinHex) + `}}`},
+		// Sub-second bounds: the second of aFrac starts before it, the second of bFrac after it
+		{"ClosedFraction", ObjectIDBetween(aFrac, bFrac, Closed), `{"_id":{"$gte":` + oid(aNextHex) + `,"$lte":` + oid(bMaxHex) + `}}`},
+		{"OpenFraction", ObjectIDBetween(aFrac, bFrac, Open), `{"_id":{"$gte":` + oid(aNextHex) + `,"$lt":` + oid(bNextHex) + `}}`},
+		{"UnboundedUpper", ObjectIDBetween(a, timi.NilTime, ClosedOpen), `{"_id":{"$gte":` + oid(aHex) + `}}`},
+		{"UnboundedLower", ObjectIDBetween(timi.NilTime, b, ClosedOpen), `{"_id":{"$lt":` + oid(bMinHex) + `}}`},
+		{"Unbounded", ObjectIDBetween(timi.NilTime, timi.NilTime, Closed), `{}`},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := bson.MarshalExtJSON(tc.filter, false, false)
+			if err != nil {
+				t.Fatalf("MarshalExtJSON failed: %v", err)
+			}
+			if string(got) != tc.expected {
+				t.Fatalf("Expected %s, got %s", tc.expected, got)
+			}
+		})
+	}
+}
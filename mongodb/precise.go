@@ -0,0 +1,134 @@
+package mongodb
+
+import (
+	"errors"
+	"reflect"
+	"time"
+
+	"github.com/ieshan/timi"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+// Precise BSON Encoding for timi.Time
+// BSON Date holds milliseconds. The precise encoding keeps full nanosecond
+// precision by storing a subdocument {date: Date, ns: Int32}, where date is
+// the time truncated to the millisecond and ns is the remaining 0-999999
+// nanoseconds. Range queries, sorting and indexes keep working on the date
+// subfield, see PreciseDatePath. Every Decoder reassembles the exact time.
+
+const (
+	// PreciseDateField is the subfield holding the BSON Date.
+	PreciseDateField = "date"
+	// PreciseNanosField is the subfield holding the sub-millisecond nanoseconds.
+	PreciseNanosField = "ns"
+)
+
+// PreciseDatePath returns the path of the Date subfield of a precise field,
+// for use in filters, sorts and indexes.
+func PreciseDatePath(field string) string {
+	return field + "." + PreciseDateField
+}
+
+// MarshalPreciseBSON marshals a timi.Time to the precise BSON encoding.
+// A null Time is stored as BSON null.
+func MarshalPreciseBSON(t timi.Time) (bson.Type, []byte, error) {
+	if !t.Valid {
+		return bson.TypeNull, nil, nil
+	}
+	ms, ns := splitPrecise(t)
+	data, err := bson.Marshal(bson.D{
+		{Key: PreciseDateField, Value: ms},
+		{Key: PreciseNanosField, Value: ns},
+	})
+	return bson.TypeEmbeddedDocument, data, err
+}
+
+// RegisterPrecise installs an encoder writing timi.Time in the precise
+// encoding, and the StrictDecoder(), on reg.
+func RegisterPrecise(reg *bson.Registry) {
+	StrictDecoder().Register(reg)
+	reg.RegisterTypeEncoder(tTimiTime, bson.ValueEncoderFunc(encodePreciseValue))
+}
+
+// PreciseTime provides a wrapper type that marshals with the precise encoding.
+// It implements bson.ValueMarshaler and bson.ValueUnmarshaler, so it works
+// with the default registry.
+type PreciseTime struct {
+	timi.Time
+}
+
+var (
+	_ bson.ValueMarshaler   = PreciseTime{}
+	_ bson.ValueUnmarshaler = (*PreciseTime)(nil)
+)
+
+func (t PreciseTime) MarshalBSONValue() (byte, []byte, error) {
+	bType, data, err := MarshalPreciseBSON(t.Time)
+	return byte(bType), data, err
+}
+
+func (t *PreciseTime) UnmarshalBSONValue(bType byte, data []byte) error {
+	timiTime, err := StrictDecoder().Unmarshal(bson.Type(bType), data)
+	if err != nil {
+		return err
+	}
+	t.Time = timiTime
+	return nil
+}
+
+// splitPrecise returns the Date and nanoseconds of t. Infinity and
+// NegInfinity are the largest and smallest Date, with no nanoseconds.
+func splitPrecise(t timi.Time) (bson.DateTime, int32) {
+	if t.IsInfinite() {
+		return dateTime(t), 0
+	}
+	return dateTime(t), int32(t.Time.Nanosecond() % 1e6)
+}
+
+func encodePreciseValue(_ bson.EncodeContext, vw bson.ValueWriter, val reflect.Value) error {
+	if !val.IsValid() || val.Type() != tTimiTime {
+		return bson.ValueEncoderError{Name: "TimiTimePreciseEncodeValue", Types: []reflect.Type{tTimiTime}, Received: val}
+	}
+	t := val.Interface().(timi.Time)
+	if !t.Valid {
+		return vw.WriteNull()
+	}
+	ms, ns := splitPrecise(t)
+	dw, err := vw.WriteDocument()
+	if err != nil {
+		return err
+	}
+	ew, err := dw.WriteDocumentElement(PreciseDateField)
+	if err != nil {
+		return err
+	}
+	if err = ew.WriteDateTime(int64(ms)); err != nil {
+		return err
+	}
+	if ew, err = dw.WriteDocumentElement(PreciseNanosField); err != nil {
+		return err
+	}
+	if err = ew.WriteInt32(ns); err != nil {
+		return err
+	}
+	return dw.WriteDocumentEnd()
+}
+
+// decodePreciseDocument reads a {date, ns} subdocument.
+func decodePreciseDocument(doc bson.Raw) (timi.Time, error) {
+	dt, ok := doc.Lookup(PreciseDateField).DateTimeOK()
+	if !ok {
+		return timi.NilTime, &DecodeError{Type: bson.TypeEmbeddedDocument, Err: errors.New("subdocument has no date time field")}
+	}
+	var ns int64
+	if v := doc.Lookup(PreciseNanosField); v.Type != 0 {
+		if ns, ok = v.AsInt64OK(); !ok || ns < 0 || ns >= 1e6 {
+			return timi.NilTime, &DecodeError{Type: bson.TypeEmbeddedDocument, Err: errors.New("subdocument has an invalid ns field")}
+		}
+	}
+	t := timeFromDateTime(dt)
+	if t.IsInfinite() {
+		return t, nil
+	}
+	return timi.Time{Time: t.Time.Add(time.Duration(ns)), Valid: true}, nil
+}
@@ -0,0 +1,143 @@
+package mongodb
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/ieshan/timi"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+func TestMarshalPreciseBSON(t *testing.T) {
+	ti := timi.Date(2024, time.January, 15, 10, 30, 45, 123456789, time.UTC)
+	bType, data, err := MarshalPreciseBSON(ti)
+	if err != nil {
+		t.Fatalf("MarshalPreciseBSON failed: %v", err)
+	}
+	if bType != bson.TypeEmbeddedDocument {
+		t.Fatalf("Expected an embedded document, got %v", bType)
+	}
+	doc := bson.Raw(data)
+	if got := doc.Lookup(PreciseDateField).Time().UTC(); !got.Equal(ti.Truncate(time.Millisecond).Time) {
+		t.Fatalf("Expected date %v, got %v", ti.Truncate(time.Millisecond), got)
+	}
+	if got := doc.Lookup(PreciseNanosField).Int32(); got != 456789 {
+		t.Fatalf("Expected 456789 ns, got %d", got)
+	}
+
+	got, err := StrictDecoder().Unmarshal(bType, data)
+	if err != nil {
+		t.Fatalf("Unmarshal failed: %v", err)
+	}
+	if !got.Equal(ti) {
+		t.Fatalf("Expected %v, got %v", ti, got)
+	}
+
+	bType, data, err = MarshalPreciseBSON(timi.NilTime)
+	if err != nil || bType != bson.TypeNull || data != nil {
+		t.Fatalf("Expected null, got %v %v %v", bType, data, err)
+	}
+}
+
+func TestPrecise_RoundTrip(t *testing.T) {
+	testCases := []timi.Time{
+		timi.Date(2024, time.January, 15, 10, 30, 45, 123456789, time.UTC),
+		timi.Date(2024, time.January, 15, 10, 30, 45, 999999999, time.UTC),
+		timi.Date(2024, time.January, 15, 10, 30, 45, 1, time.UTC),
+		timi.Date(1969, time.December, 31, 23, 59, 59, 999999999, time.UTC),
+		timi.Date(1900, time.March, 1, 0, 0, 0, 500, time.UTC),
+	}
+	for _, ti := range testCases {
+		bType, data, err := MarshalPreciseBSON(ti)
+		if err != nil {
+			t.Fatalf("MarshalPreciseBSON failed: %v", err)
+		}
+		got, err := LenientDecoder().Unmarshal(bType, data)
+		if err != nil {
+			t.Fatalf("Unmarshal failed: %v", err)
+		}
+		if !got.Equal(ti) {
+			t.Fatalf("Expected %v, got %v", ti, got)
+		}
+	}
+}
+
+func TestPreciseTime(t *testing.T) {
+	ti := timi.Date(2024, time.January, 15, 10, 30, 45, 123456789, time.UTC)
+	raw, err := bson.Marshal(struct {
+		At   PreciseTime `bson:"at"`
+		Null PreciseTime `bson:"null"`
+	}{At: PreciseTime{ti}, Null: PreciseTime{timi.NilTime}})
+	if err != nil {
+		t.Fatalf("Marshal failed: %v", err)
+	}
+
+	var got struct {
+		At   PreciseTime `bson:"at"`
+		Null PreciseTime `bson:"null"`
+	}
+	if err := bson.Unmarshal(raw, &got); err != nil {
+		t.Fatalf("Unmarshal failed: %v", err)
+	}
+	if !got.At.Equal(ti) {
+		t.Fatalf("Expected %v, got %v", ti, got.At.Time)
+	}
+	if !got.Null.IsNull() {
+		t.Fatalf("Expected null, got %v", got.Null.Time)
+	}
+}
+
+func TestRegisterPrecise(t *testing.T) {
+	ti := timi.Date(2024, time.January, 15, 10, 30, 45, 123456789, time.UTC)
+	reg := bson.NewRegistry()
+	RegisterPrecise(reg)
+
+	buf := new(bytes.Buffer)
+	enc := bson.NewEncoder(bson.NewDocumentWriter(buf))
+	enc.SetRegistry(reg)
+	if err := enc.Encode(codecTestDoc{TimeField: ti, NullTime: timi.NilTime}); err != nil {
+		t.Fatalf("Encode failed: %v", err)
+	}
+	raw := bson.Raw(buf.Bytes())
+	if v := raw.Lookup("time_field", PreciseDateField); v.Type != bson.TypeDateTime {
+		t.Fatalf("Expected a DateTime date subfield, got %v", v.Type)
+	}
+	if v := raw.Lookup("null_time"); v.Type != bson.TypeNull {
+		t.Fatalf("Expected null, got %v", v.Type)
+	}
+
+	dec := bson.NewDecoder(bson.NewDocumentReader(bytes.NewReader(raw)))
+	dec.SetRegistry(reg)
+	var got codecTestDoc
+	if err := dec.Decode(&got); err != nil {
+		t.Fatalf("Decode failed: %v", err)
+	}
+	if !got.TimeField.Equal(ti) {
+		t.Fatalf("Expected %v, got %v", ti, got.TimeField)
+	}
+	if !got.NullTime.IsNull() {
+		t.Fatalf("Expected null, got %v", got.NullTime)
+	}
+}
+
+func TestPrecise_InvalidNanos(t *testing.T) {
+	for _, ns := range []any{int32(-1), int32(1e6), "1"} {
+		data, err := bson.Marshal(bson.D{
+			{Key: PreciseDateField, Value: bson.DateTime(0)},
+			{Key: PreciseNanosField, Value: ns},
+		})
+		if err != nil {
+			t.Fatalf("Marshal failed: %v", err)
+		}
+		if _, err := StrictDecoder().Unmarshal(bson.TypeEmbeddedDocument, data); err == nil {
+			t.Fatalf("Expected an error for ns %v", ns)
+		}
+	}
+}
+
+func TestPreciseDatePath(t *testing.T) {
+	if got := PreciseDatePath("created_at"); got != "created_at.date" {
+		t.Fatalf("Expected created_at.date, got %s", got)
+	}
+}
@@ -0,0 +1,115 @@
+package mongodb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/ieshan/timi"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+// BSON Timestamp Helper Functions for timi.Time
+// Change streams and the oplog order operations by BSON Timestamp: Unix
+// seconds plus an increment counting the operations within that second. A
+// timi.Time holds the seconds; OperationTime also keeps the increment, so
+// operations within the same second stay in order.
+
+// OperationTime is a BSON Timestamp as a timi.Time and its increment.
+type OperationTime struct {
+	Time      timi.Time
+	Increment uint32
+}
+
+// OperationTimeFromTimestamp converts ts to an OperationTime.
+// The zero Timestamp returns an OperationTime with a null Time.
+func OperationTimeFromTimestamp(ts bson.Timestamp) OperationTime {
+	return OperationTime{Time: TimeFromTimestamp(ts), Increment: ts.I}
+}
+
+// Timestamp converts o back to a BSON Timestamp. A null Time returns the
+// zero Timestamp.
+func (o OperationTime) Timestamp() bson.Timestamp {
+	if !o.Time.Valid {
+		return bson.Timestamp{}
+	}
+	return bson.Timestamp{T: timestampSeconds(o.Time), I: o.Increment}
+}
+
+// Compare compares o with p by time, then by increment. If o is before p, it
+// returns -1; if o is after p, it returns +1; if they're the same, it
+// returns 0.
+func (o OperationTime) Compare(p OperationTime) int {
+	return o.Timestamp().Compare(p.Timestamp())
+}
+
+// Before reports whether o is before p.
+func (o OperationTime) Before(p OperationTime) bool {
+	return o.Compare(p) < 0
+}
+
+// After reports whether o is after p.
+func (o OperationTime) After(p OperationTime) bool {
+	return o.Compare(p) > 0
+}
+
+// TimeFromTimestamp returns the seconds of ts as a timi.Time, dropping the
+// increment. The zero Timestamp returns timi.NilTime.
+func TimeFromTimestamp(ts bson.Timestamp) timi.Time {
+	if ts.IsZero() {
+		return timi.NilTime
+	}
+	return timi.Time{Time: time.Unix(int64(ts.T), 0).UTC(), Valid: true}
+}
+
+// TimestampAt returns the first BSON Timestamp of the second holding t, with
+// an increment of 0. Starting a change stream there never misses an
+// operation at or after t, but can replay operations earlier in that second.
+// A null t returns the zero Timestamp.
+func TimestampAt(t timi.Time) bson.Timestamp {
+	if !t.Valid {
+		return bson.Timestamp{}
+	}
+	return bson.Timestamp{T: timestampSeconds(t)}
+}
+
+// timestampSeconds clamps the Unix seconds of t to the uint32 range of a
+// BSON Timestamp.
+func timestampSeconds(t timi.Time) uint32 {
+	return uint32(max(0, min(t.Unix(), math.MaxUint32)))
+}
+
+// Watcher is implemented by *mongo.Client, *mongo.Database and
+// *mongo.Collection.
+type Watcher interface {
+	Watch(ctx context.Context, pipeline any, opts ...options.Lister[options.ChangeStreamOptions]) (*mongo.ChangeStream, error)
+}
+
+// WatchFrom opens a change stream on w starting at TimestampAt(t) with
+// startAtOperationTime. opts are applied after the start time, and must not
+// set a resume token.
+func WatchFrom(ctx context.Context, w Watcher, pipeline any, t timi.Time, opts ...options.Lister[options.ChangeStreamOptions]) (*mongo.ChangeStream, error) {
+	if !t.Valid {
+		return nil, errors.New("timi/mongodb: change stream start time is null")
+	}
+	ts := TimestampAt(t)
+	return w.Watch(ctx, pipeline, append([]options.Lister[options.ChangeStreamOptions]{options.ChangeStream().SetStartAtOperationTime(&ts)}, opts...)...)
+}
+
+// EventTimes returns the clusterTime and wallTime of a change event.
+// wallTime is timi.NilTime for servers that do not report it.
+func EventTimes(event bson.Raw) (OperationTime, timi.Time, error) {
+	sec, inc, ok := event.Lookup("clusterTime").TimestampOK()
+	if !ok {
+		return OperationTime{}, timi.NilTime, errors.New("timi/mongodb: change event has no clusterTime timestamp")
+	}
+	wall, err := LenientDecoder().Lookup(event, "wallTime")
+	if err != nil {
+		return OperationTime{}, timi.NilTime, fmt.Errorf("timi/mongodb: change event wallTime: %w", err)
+	}
+	return OperationTimeFromTimestamp(bson.Timestamp{T: sec, I: inc}), wall, nil
+}
@@ -0,0 +1,134 @@
+package mongodb
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/ieshan/timi"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+func TestOperationTime(t *testing.T) {
+	sec := uint32(time.Date(2024, time.January, 15, 10, 30, 45, 0, time.UTC).Unix())
+	ts := bson.Timestamp{T: sec, I: 7}
+
+	op := OperationTimeFromTimestamp(ts)
+	if !op.Time.Equal(timi.Date(2024, time.January, 15, 10, 30, 45, 0, time.UTC)) || op.Increment != 7 {
+		t.Fatalf("Expected 2024-01-15 10:30:45 #7, got %v #%d", op.Time, op.Increment)
+	}
+	if got := op.Timestamp(); got != ts {
+		t.Fatalf("Expected %v, got %v", ts, got)
+	}
+
+	// Operations in the same second are ordered by increment
+	first := OperationTimeFromTimestamp(bson.Timestamp{T: sec, I: 1})
+	second := OperationTimeFromTimestamp(bson.Timestamp{T: sec, I: 2})
+	later := OperationTimeFromTimestamp(bson.Timestamp{T: sec + 1, I: 1})
+	if !first.Time.Equal(second.Time) {
+		t.Fatalf("Expected the same second, got %v and %v", first.Time, second.Time)
+	}
+	if !first.Before(second) || !second.Before(later) || !later.After(first) {
+		t.Fatalf("Expected %v < %v < %v", first, second, later)
+	}
+	if first.Compare(first) != 0 || second.Compare(first) != 1 || first.Compare(later) != -1 {
+		t.Fatalf("Unexpected Compare results")
+	}
+
+	zero := OperationTimeFromTimestamp(bson.Timestamp{})
+	if zero.Time.Valid || !zero.Timestamp().IsZero() {
+		t.Fatalf("Expected a null time for the zero Timestamp, got %v", zero)
+	}
+}
+
+func TestTimestampAt(t *testing.T) {
+	ti := timi.Date(2024, time.January, 15, 10, 30, 45, 999000000, time.UTC)
+	ts := TimestampAt(ti)
+	if ts.T != uint32(ti.Unix()) || ts.I != 0 {
+		t.Fatalf("Expected {%d 0}, got %v", ti.Unix(), ts)
+	}
+	if got := TimeFromTimestamp(ts); !got.Equal(ti.Truncate(time.Second)) {
+		t.Fatalf("Expected %v, got %v", ti.Truncate(time.Second), got)
+	}
+	if !TimestampAt(timi.NilTime).IsZero() {
+		t.Fatalf("Expected the zero Timestamp for a null time")
+	}
+	if TimeFromTimestamp(bson.Timestamp{}).Valid {
+		t.Fatalf("Expected a null time for the zero Timestamp")
+	}
+}
+
+type fakeWatcher struct {
+	opts options.ChangeStreamOptions
+}
+
+func (w *fakeWatcher) Watch(_ context.Context, _ any, opts ...options.Lister[options.ChangeStreamOptions]) (*mongo.ChangeStream, error) {
+	for _, o := range opts {
+		for _, set := range o.List() {
+			if err := set(&w.opts); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return nil, nil
+}
+
+func TestWatchFrom(t *testing.T) {
+	ti := timi.Date(2024, time.January, 15, 10, 30, 45, 0, time.UTC)
+	w := &fakeWatcher{}
+	if _, err := WatchFrom(context.Background(), w, mongo.Pipeline{}, ti, options.ChangeStream().SetBatchSize(10)); err != nil {
+		t.Fatalf("WatchFrom failed: %v", err)
+	}
+	if w.opts.StartAtOperationTime == nil || *w.opts.StartAtOperationTime != TimestampAt(ti) {
+		t.Fatalf("Expected startAtOperationTime %v, got %v", TimestampAt(ti), w.opts.StartAtOperationTime)
+	}
+	if w.opts.BatchSize == nil || *w.opts.BatchSize != 10 {
+		t.Fatalf("Expected batch size 10, got %v", w.opts.BatchSize)
+	}
+
+	if _, err := WatchFrom(context.Background(), w, mongo.Pipeline{}, timi.NilTime); err == nil {
+		t.Fatalf("Expected an error for a null start time")
+	}
+}
+
+func TestEventTimes(t *testing.T) {
+	wall := timi.Date(2024, time.January, 15, 10, 30, 45, 123000000, time.UTC)
+	ts := bson.Timestamp{T: uint32(wall.Unix()), I: 3}
+	event, err := bson.Marshal(bson.D{
+		{Key: "operationType", Value: "insert"},
+		{Key: "clusterTime", Value: ts},
+		{Key: "wallTime", Value: bson.NewDateTimeFromTime(wall.Time)},
+	})
+	if err != nil {
+		t.Fatalf("Marshal failed: %v", err)
+	}
+
+	cluster, gotWall, err := EventTimes(event)
+	if err != nil {
+		t.Fatalf("EventTimes failed: %v", err)
+	}
+	if cluster.Timestamp() != ts {
+		t.Fatalf("Expected clusterTime %v, got %v", ts, cluster.Timestamp())
+	}
+	if !gotWall.Equal(wall) {
+		t.Fatalf("Expected wallTime %v, got %v", wall, gotWall)
+	}
+
+	event, err = bson.Marshal(bson.D{{Key: "clusterTime", Value: ts}})
+	if err != nil {
+		t.Fatalf("Marshal failed: %v", err)
+	}
+	if _, gotWall, err = EventTimes(event); err != nil || gotWall.Valid {
+		t.Fatalf("Expected a null wallTime, got %v, %v", gotWall, err)
+	}
+
+	event, err = bson.Marshal(bson.D{{Key: "operationType", Value: "insert"}})
+	if err != nil {
+		t.Fatalf("Marshal failed: %v", err)
+	}
+	if _, _, err = EventTimes(event); err == nil {
+		t.Fatalf("Expected an error without clusterTime")
+	}
+}
@@ -0,0 +1,105 @@
+package mongodb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/ieshan/timi"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+// TTL Helper Functions for timi.Time
+// The TTL monitor deletes a document once the Date in its indexed field,
+// plus the index's expireAfterSeconds, is in the past. It silently ignores
+// documents where the field is null, missing, or not a Date, such as a
+// legacy {time, valid} subdocument or a string. Register the codec so
+// timi.Time fields are stored as Date.
+
+// TTLIndex returns the index model of a TTL index on field. Documents expire
+// after the time in the field plus after, which is rounded down to whole
+// seconds. Use an after of 0 for fields holding the expiry time itself, as
+// set by ExpireAt and ExpireIn.
+func TTLIndex(field string, after time.Duration) (mongo.IndexModel, error) {
+	secs := after / time.Second
+	if secs < 0 || secs > math.MaxInt32 {
+		return mongo.IndexModel{}, fmt.Errorf("timi/mongodb: TTL of %v is out of range", after)
+	}
+	return mongo.IndexModel{
+		Keys:    bson.D{{Key: field, Value: 1}},
+		Options: options.Index().SetExpireAfterSeconds(int32(secs)),
+	}, nil
+}
+
+// HasTTLIndex reports whether col has a TTL index on field alone.
+func HasTTLIndex(ctx context.Context, col *mongo.Collection, field string) (bool, error) {
+	specs, err := col.Indexes().ListSpecifications(ctx)
+	if err != nil {
+		return false, err
+	}
+	for _, spec := range specs {
+		if spec.ExpireAfterSeconds == nil {
+			continue
+		}
+		keys, err := spec.KeysDocument.Elements()
+		if err != nil {
+			return false, err
+		}
+		if len(keys) == 1 && keys[0].Key() == field {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// ExpireAt returns t as an expiry time. The result is truncated to the
+// millisecond precision of BSON Date.
+func ExpireAt(t time.Time) timi.Time {
+	return timi.Time{Time: t.UTC().Truncate(time.Millisecond), Valid: true}
+}
+
+// ExpireIn returns the expiry time d from now.
+func ExpireIn(d time.Duration) timi.Time {
+	return ExpireAt(time.Now().Add(d))
+}
+
+// NonExpiringFilter matches documents the TTL monitor ignores because field
+// is null, missing, or not a Date.
+func NonExpiringFilter(field string) bson.D {
+	return bson.D{{Key: field, Value: bson.D{{Key: "$not", Value: bson.D{{Key: "$type", Value: "date"}}}}}}
+}
+
+// CheckTTLField counts the documents of col the TTL monitor ignores, by the
+// aggregation type name of field: "null", "missing", "object", "string" and
+// so on. An empty map means every document can expire.
+func CheckTTLField(ctx context.Context, col *mongo.Collection, field string) (map[string]int64, error) {
+	if field == "" {
+		return nil, errors.New("timi/mongodb: no TTL field")
+	}
+	cursor, err := col.Aggregate(ctx, mongo.Pipeline{
+		{{Key: "$match", Value: NonExpiringFilter(field)}},
+		{{Key: "$group", Value: bson.D{
+			{Key: "_id", Value: bson.D{{Key: "$type", Value: "$" + field}}},
+			{Key: "count", Value: bson.D{{Key: "$sum", Value: 1}}},
+		}}},
+	})
+	if err != nil {
+		return nil, err
+	}
+	var results []struct {
+		Type  string `bson:"_id"`
+		Count int64  `bson:"count"`
+	}
+	if err = cursor.All(ctx, &results); err != nil {
+		return nil, err
+	}
+	counts := make(map[string]int64, len(results))
+	for _, r := range results {
+		counts[r.Type] = r.Count
+	}
+	return counts, nil
+}
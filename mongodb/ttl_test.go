@@ -0,0 +1,75 @@
+package mongodb
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+func TestTTLIndex(t *testing.T) {
+	model, err := TTLIndex("expired_at", 90*time.Minute+500*time.Millisecond)
+	if err != nil {
+		t.Fatalf("TTLIndex failed: %v", err)
+	}
+	keys, err := bson.MarshalExtJSON(model.Keys, false, false)
+	if err != nil {
+		t.Fatalf("MarshalExtJSON failed: %v", err)
+	}
+	if string(keys) != `{"expired_at":1}` {
+		t.Fatalf("Expected {\"expired_at\":1}, got %s", keys)
+	}
+
+	var opts options.IndexOptions
+	for _, set := range model.Options.List() {
+		if err := set(&opts); err != nil {
+			t.Fatalf("Applying index options failed: %v", err)
+		}
+	}
+	if opts.ExpireAfterSeconds == nil || *opts.ExpireAfterSeconds != 5400 {
+		t.Fatalf("Expected expireAfterSeconds 5400, got %v", opts.ExpireAfterSeconds)
+	}
+
+	for _, after := range []time.Duration{-time.Second, (1 << 31) * time.Second} {
+		if _, err := TTLIndex("expired_at", after); err == nil {
+			t.Fatalf("Expected an error for %v", after)
+		}
+	}
+}
+
+func TestExpireAtIn(t *testing.T) {
+	at := time.Date(2024, time.January, 15, 10, 30, 45, 123456789, time.FixedZone("EST", -5*3600))
+	got := ExpireAt(at)
+	if !got.Valid || !got.Time.Equal(at.Truncate(time.Millisecond)) {
+		t.Fatalf("Expected %v, got %v", at.Truncate(time.Millisecond), got)
+	}
+	if got.Time.Location() != time.UTC {
+		t.Fatalf("Expected UTC, got %v", got.Time.Location())
+	}
+
+	before := time.Now().Add(time.Hour).Truncate(time.Millisecond)
+	got = ExpireIn(time.Hour)
+	after := time.Now().Add(time.Hour)
+	if !got.Valid || got.Time.Before(before) || got.Time.After(after) {
+		t.Fatalf("Expected a time between %v and %v, got %v", before, after, got)
+	}
+}
+
+func TestNonExpiringFilter(t *testing.T) {
+	got, err := bson.MarshalExtJSON(NonExpiringFilter("expired_at"), false, false)
+	if err != nil {
+		t.Fatalf("MarshalExtJSON failed: %v", err)
+	}
+	expected := `{"expired_at":{"$not":{"$type":"date"}}}`
+	if string(got) != expected {
+		t.Fatalf("Expected %s, got %s", expected, got)
+	}
+}
+
+func TestCheckTTLField_NoField(t *testing.T) {
+	if _, err := CheckTTLField(context.Background(), nil, ""); err == nil {
+		t.Fatalf("Expected an error without a field")
+	}
+}
@@ -0,0 +1,107 @@
+package timi
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"time"
+)
+
+// Storage Zones
+// Columns without a time zone, such as MySQL DATETIME and PostgreSQL
+// timestamp, store a wall clock. Time.Value passes a time.Time, which drivers
+// write in the zone of the connection, such as loc of go-sql-driver/mysql or
+// the TimeZone setting of PostgreSQL, so the stored wall clock depends on the
+// session. Naive declares the zone of the stored wall clock instead: Value
+// writes the wall clock in that zone as text, which no driver converts, and
+// Scan reads the wall clock back in that zone, ignoring the location the
+// driver attached.
+//
+// On SQLite, declare the column as TEXT rather than DATETIME, so that the
+// driver returns the stored text instead of a time.Time in its _loc zone.
+// In zones with daylight saving time, a wall clock in the repeated hour is
+// ambiguous; NaiveUTC has no such hour.
+
+// NaiveLayout is the layout of the wall clock Naive writes.
+const NaiveLayout = "2006-01-02 15:04:05.999999999"
+
+var naiveLayouts = []string{
+	NaiveLayout,
+	"2006-01-02T15:04:05.999999999",
+	"2006-01-02",
+}
+
+// StorageZone is the zone of the wall clock stored in a column. Declare a
+// named zone with an empty struct type:
+//
+//	var berlin, _ = time.LoadLocation("Europe/Berlin")
+//
+//	type Berlin struct{}
+//
+//	func (Berlin) Location() *time.Location { return berlin }
+type StorageZone interface {
+	Location() *time.Location
+}
+
+// UTCZone is the StorageZone of columns that store the UTC wall clock.
+type UTCZone struct{}
+
+// Location returns time.UTC.
+func (UTCZone) Location() *time.Location {
+	return time.UTC
+}
+
+// Naive is a Time stored in a column without a time zone as the wall clock
+// of the zone Z, whatever the zone of the connection.
+type Naive[Z StorageZone] struct{ Time }
+
+// NaiveUTC is a Time stored as the UTC wall clock.
+type NaiveUTC = Naive[UTCZone]
+
+// Value implements the driver.Valuer interface.
+func (t Naive[Z]) Value() (driver.Value, error) {
+	if !t.Valid {
+		return nil, nil
+	}
+	if inf := t.infinityText(); inf != "" {
+		return inf, nil
+	}
+	var z Z
+	return t.Time.Time.In(z.Location()).Format(NaiveLayout), nil
+}
+
+// Scan implements the sql.Scanner interface. A time.Time is read by its wall
+// clock, in the location the driver parsed it in; text is read as a wall
+// clock in NaiveLayout, with or without the fraction or the time of day.
+func (t *Naive[Z]) Scan(value interface{}) error {
+	var z Z
+	loc := z.Location()
+	switch v := value.(type) {
+	case nil:
+		t.Time = NilTime
+	case time.Time:
+		year, month, day := v.Date()
+		hour, min, sec := v.Clock()
+		t.Time = Time{Time: time.Date(year, month, day, hour, min, sec, v.Nanosecond(), loc).UTC(), Valid: true}
+	case []byte:
+		return t.scanText(string(v), loc)
+	case string:
+		return t.scanText(v, loc)
+	default:
+		return fmt.Errorf("timi: cannot scan %T into a naive time", value)
+	}
+	return nil
+}
+
+func (t *Naive[Z]) scanText(s string, loc *time.Location) error {
+	if inf, ok := parseInfinityText(s); ok {
+		t.Time = inf
+		return nil
+	}
+	for _, layout := range naiveLayouts {
+		if tv, err := time.ParseInLocation(layout, s, loc); err == nil {
+			t.Time = Time{Time: tv.UTC(), Valid: true}
+			return nil
+		}
+	}
+	return fmt.Errorf("timi: cannot parse %q as a naive time", s)
+}
@@ -0,0 +1,215 @@
+package timi
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"unicode"
+)
+
+// Optional is a Time that also records whether it was provided at all,
+// for PATCH style updates. It has three states:
+//
+//   - Unset: the field was absent (Present is false)
+//   - Null: the field was provided as null (Present is true, Time.Valid is false)
+//   - Set: the field was provided with a time (Present is true, Time.Valid is true)
+//
+// UnmarshalJSON is only called for keys that are present in the input, so an
+// absent key leaves the zero value, Unset. Use the omitzero (or, for BSON,
+// omitempty) struct tag option so that Unset fields are omitted when
+// encoding; without it an Unset field encodes like Null.
+type Optional struct {
+	Time    Time
+	Present bool
+}
+
+// OptionalOf returns an Optional holding t. It is Null when t is NilTime.
+func OptionalOf(t Time) Optional {
+	return Optional{Time: t, Present: true}
+}
+
+// OptionalNull returns a Null Optional.
+func OptionalNull() Optional {
+	return Optional{Time: NilTime, Present: true}
+}
+
+// IsUnset reports whether o was absent.
+func (o Optional) IsUnset() bool {
+	return !o.Present
+}
+
+// IsNull reports whether o was provided as null.
+func (o Optional) IsNull() bool {
+	return o.Present && !o.Time.Valid
+}
+
+// IsSet reports whether o was provided with a time.
+func (o Optional) IsSet() bool {
+	return o.Present && o.Time.Valid
+}
+
+// IsZero reports whether o is Unset. It lets the omitzero option of
+// encoding/json and the omitempty option of the BSON driver omit Unset
+// fields while keeping Null ones.
+func (o Optional) IsZero() bool {
+	return !o.Present
+}
+
+// Get returns the time and whether o was provided.
+func (o Optional) Get() (Time, bool) {
+	return o.Time, o.Present
+}
+
+// Apply stores the time in *dst when o was provided, and leaves it
+// unchanged when o is Unset.
+func (o Optional) Apply(dst *Time) {
+	if o.Present {
+		*dst = o.Time
+	}
+}
+
+func (o Optional) MarshalJSON() ([]byte, error) {
+	return o.Time.MarshalJSON()
+}
+
+func (o *Optional) UnmarshalJSON(data []byte) error {
+	if err := o.Time.UnmarshalJSON(data); err != nil {
+		return err
+	}
+	o.Present = true
+	return nil
+}
+
+var (
+	typeTime     = reflect.TypeOf(Time{})
+	typeOptional = reflect.TypeOf(Optional{})
+)
+
+// ApplyPatch copies every provided Optional field of the struct patch onto
+// the field with the same name in the struct pointed to by dst. Destination
+// fields can be a Time or an Optional. Unset fields, and patch fields of
+// other types, are skipped.
+func ApplyPatch(dst, patch any) error {
+	dv := reflect.ValueOf(dst)
+	if dv.Kind() != reflect.Pointer || dv.IsNil() || dv.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("timi: ApplyPatch destination must be a non-nil struct pointer, got %T", dst)
+	}
+	dv = dv.Elem()
+	pv, err := patchStruct(patch)
+	if err != nil {
+		return err
+	}
+	for i := range pv.NumField() {
+		sf := pv.Type().Field(i)
+		if sf.Type != typeOptional || !sf.IsExported() {
+			continue
+		}
+		o := pv.Field(i).Interface().(Optional)
+		if !o.Present {
+			continue
+		}
+		df := dv.FieldByName(sf.Name)
+		if !df.IsValid() || !df.CanSet() {
+			return fmt.Errorf("timi: ApplyPatch destination %s has no settable field %s", dv.Type(), sf.Name)
+		}
+		switch df.Type() {
+		case typeTime:
+			df.Set(reflect.ValueOf(o.Time))
+		case typeOptional:
+			df.Set(reflect.ValueOf(o))
+		default:
+			return fmt.Errorf("timi: ApplyPatch destination field %s is %s, not timi.Time or timi.Optional", sf.Name, df.Type())
+		}
+	}
+	return nil
+}
+
+// UpdateColumns returns the SQL UPDATE column map of the provided Optional
+// fields of the struct patch. Null fields map to NilTime, which is written
+// as NULL. The column name is taken from the column setting of the gorm
+// struct tag, then the db struct tag, or else is the snake_case field name,
+// as GORM names columns by default. Fields tagged gorm:"-" or db:"-" are
+// skipped. The map can be passed to GORM's Updates or used to build an
+// UPDATE statement.
+func UpdateColumns(patch any) (map[string]any, error) {
+	pv, err := patchStruct(patch)
+	if err != nil {
+		return nil, err
+	}
+	columns := make(map[string]any)
+	for i := range pv.NumField() {
+		sf := pv.Type().Field(i)
+		if sf.Type != typeOptional || !sf.IsExported() {
+			continue
+		}
+		o := pv.Field(i).Interface().(Optional)
+		if !o.Present {
+			continue
+		}
+		name, ok := columnName(sf)
+		if !ok {
+			continue
+		}
+		columns[name] = o.Time
+	}
+	return columns, nil
+}
+
+// columnName returns the column of sf for UpdateColumns, or false for an
+// ignored field.
+func columnName(sf reflect.StructField) (string, bool) {
+	for _, setting := range strings.Split(sf.Tag.Get("gorm"), ";") {
+		key, value, _ := strings.Cut(strings.TrimSpace(setting), ":")
+		switch strings.ToUpper(strings.TrimSpace(key)) {
+		case "-":
+			if value == "" || value == "all" {
+				return "", false
+			}
+		case "COLUMN":
+			if value = strings.TrimSpace(value); value != "" {
+				return value, true
+			}
+		}
+	}
+	name, _, _ := strings.Cut(sf.Tag.Get("db"), ",")
+	switch name {
+	case "-":
+		return "", false
+	case "":
+		return snakeCase(sf.Name), true
+	}
+	return name, true
+}
+
+func patchStruct(patch any) (reflect.Value, error) {
+	pv := reflect.ValueOf(patch)
+	if pv.Kind() == reflect.Pointer {
+		if pv.IsNil() {
+			return reflect.Value{}, errors.New("timi: patch is a nil pointer")
+		}
+		pv = pv.Elem()
+	}
+	if pv.Kind() != reflect.Struct {
+		return reflect.Value{}, fmt.Errorf("timi: patch must be a struct, got %T", patch)
+	}
+	return pv, nil
+}
+
+// snakeCase converts a Go field name to snake_case, keeping initialisms
+// together: EndTime is end_time and UserID is user_id.
+func snakeCase(name string) string {
+	runes := []rune(name)
+	var b strings.Builder
+	for i, r := range runes {
+		if unicode.IsUpper(r) {
+			if i > 0 && (unicode.IsLower(runes[i-1]) || unicode.IsDigit(runes[i-1]) ||
+				(i+1 < len(runes) && unicode.IsLower(runes[i+1]))) {
+				b.WriteByte('_')
+			}
+			r = unicode.ToLower(r)
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
@@ -0,0 +1,362 @@
+package pgx
+
+import (
+	"database/sql/driver"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"time"
+
+	"github.com/ieshan/timi"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// pgx Codec for timi.Time
+// Without a codec, pgx encodes and scans timi.Time through its database/sql
+// Value and Scan methods, in text format. Registering the codec encodes and
+// scans timi.Time directly in the binary format of timestamp and timestamptz,
+// and their arrays. Both store microseconds, so finer precision is truncated.
+// A timestamp column holds the UTC wall clock of the time. Finite times outside
+// the timestamp range of PostgreSQL fail to encode.
+
+// Infinity selects how PostgreSQL infinity and -infinity are scanned into
+// timi.Time. timi.Infinity and timi.NegInfinity are always encoded as
+// infinity and -infinity.
+type Infinity int
+
+const (
+	// InfinityError fails to scan infinity and -infinity with ErrInfinity.
+	// It is the zero value of a Codec's Infinity field.
+	InfinityError Infinity = iota
+	// InfinityNull scans infinity and -infinity as timi.NilTime.
+	InfinityNull
+	// InfinityClamp scans infinity as MaxTime and -infinity as MinTime, and
+	// encodes times at or after MaxTime as infinity and times at or before
+	// MinTime as -infinity, so both round-trip.
+	InfinityClamp
+	// InfinityValue scans infinity as timi.Infinity and -infinity as
+	// timi.NegInfinity, so they round-trip. Register uses it.
+	InfinityValue
+)
+
+var (
+	// MaxTime is the first instant after the timestamp range of PostgreSQL,
+	// 294277-01-01 00:00:00 UTC.
+	MaxTime = timi.Time{Time: time.Date(294277, time.January, 1, 0, 0, 0, 0, time.UTC), Valid: true}
+	// MinTime is an instant before the timestamp range of PostgreSQL,
+	// 4714-01-01 00:00:00 BC UTC.
+	MinTime = timi.Time{Time: time.Date(-4713, time.January, 1, 0, 0, 0, 0, time.UTC), Valid: true}
+
+	// minTimestamp is the first instant of the timestamp range of
+	// PostgreSQL, 4714-11-24 00:00:00 BC UTC.
+	minTimestamp = time.Date(-4713, time.November, 24, 0, 0, 0, 0, time.UTC)
+)
+
+// ErrInfinity is returned when scanning infinity or -infinity with
+// InfinityError.
+var ErrInfinity = errors.New("timi/pgx: cannot scan infinity into timi.Time")
+
+const (
+	secFromUnixEpochToY2K   = 946684800
+	infinityMicroseconds    = math.MaxInt64
+	negInfinityMicroseconds = math.MinInt64
+
+	timestampFormat   = "2006-01-02 15:04:05.999999999"
+	timestamptzFormat = "2006-01-02 15:04:05.999999999Z07:00:00"
+)
+
+var (
+	tTimestamp   *pgtype.Timestamp
+	tTimestamptz *pgtype.Timestamptz
+)
+
+// Register installs the codec with InfinityValue on m for timestamp,
+// timestamptz, timestamp[] and timestamptz[], and maps timi.Time to
+// timestamptz for parameters of unknown type, so infinity and -infinity scan
+// as timi.Infinity and timi.NegInfinity, as they do with timi.Time.Scan. For
+// a connection pool, call it from pgxpool.Config.AfterConnect with
+// conn.TypeMap().
+func Register(m *pgtype.Map) {
+	RegisterWithInfinity(m, InfinityValue)
+}
+
+// RegisterWithInfinity installs the codec on m like Register, with the given
+// infinity mapping.
+func RegisterWithInfinity(m *pgtype.Map, inf Infinity) {
+	for _, tp := range []struct {
+		name          string
+		oid, arrayOID uint32
+		next          pgtype.Codec
+	}{
+		{"timestamp", pgtype.TimestampOID, pgtype.TimestampArrayOID, &pgtype.TimestampCodec{}},
+		{"timestamptz", pgtype.TimestamptzOID, pgtype.TimestamptzArrayOID, &pgtype.TimestamptzCodec{}},
+	} {
+		next := tp.next
+		if dt, ok := m.TypeForOID(tp.oid); ok {
+			// Keep the settings of the registered codec, such as ScanLocation
+			next = dt.Codec
+			if c, ok := next.(*Codec); ok {
+				next = c.Next
+			}
+		}
+		elem := &pgtype.Type{Name: tp.name, OID: tp.oid, Codec: &Codec{Next: next, Infinity: inf}}
+		m.RegisterType(elem)
+		m.RegisterType(&pgtype.Type{Name: "_" + tp.name, OID: tp.arrayOID, Codec: &pgtype.ArrayCodec{ElementType: elem}})
+	}
+	m.RegisterDefaultPgType(timi.Time{}, "timestamptz")
+	m.RegisterDefaultPgType([]timi.Time{}, "_timestamptz")
+}
+
+// Codec is a pgtype.Codec for timestamp and timestamptz that encodes and
+// scans timi.Time. Other values, such as time.Time and pgtype.Timestamptz,
+// are handled by Next.
+type Codec struct {
+	// Next is the codec for other values, usually a *pgtype.TimestampCodec
+	// or a *pgtype.TimestamptzCodec.
+	Next pgtype.Codec
+	// Infinity selects how infinity and -infinity map to timi.Time.
+	Infinity Infinity
+}
+
+func (c *Codec) FormatSupported(format int16) bool {
+	return format == pgtype.TextFormatCode || format == pgtype.BinaryFormatCode
+}
+
+func (c *Codec) PreferredFormat() int16 {
+	return pgtype.BinaryFormatCode
+}
+
+func (c *Codec) PlanEncode(m *pgtype.Map, oid uint32, format int16, value any) pgtype.EncodePlan {
+	if _, ok := value.(timi.Time); !ok {
+		return c.Next.PlanEncode(m, oid, format, value)
+	}
+	switch format {
+	case pgtype.BinaryFormatCode:
+		return encodePlanBinary{c}
+	case pgtype.TextFormatCode:
+		return encodePlanText{c: c, layout: textLayout(oid)}
+	}
+	return nil
+}
+
+func (c *Codec) PlanScan(m *pgtype.Map, oid uint32, format int16, target any) pgtype.ScanPlan {
+	if _, ok := target.(*timi.Time); !ok {
+		return c.Next.PlanScan(m, oid, format, target)
+	}
+	switch format {
+	case pgtype.BinaryFormatCode:
+		return scanPlanBinary{c}
+	case pgtype.TextFormatCode:
+		// pgtype parses the text formats, including BC dates and the
+		// offsets of the session time zone
+		var next pgtype.ScanPlan
+		if oid == pgtype.TimestampOID {
+			next = c.Next.PlanScan(m, oid, format, tTimestamp)
+		} else {
+			next = c.Next.PlanScan(m, oid, format, tTimestamptz)
+		}
+		if next == nil {
+			return nil
+		}
+		return &scanPlanText{c: c, oid: oid, next: next}
+	}
+	return nil
+}
+
+func (c *Codec) DecodeDatabaseSQLValue(m *pgtype.Map, oid uint32, format int16, src []byte) (driver.Value, error) {
+	return c.Next.DecodeDatabaseSQLValue(m, oid, format, src)
+}
+
+func (c *Codec) DecodeValue(m *pgtype.Map, oid uint32, format int16, src []byte) (any, error) {
+	return c.Next.DecodeValue(m, oid, format, src)
+}
+
+// infinity reports whether t is encoded as infinity (+1) or -infinity (-1).
+func (c *Codec) infinity(t timi.Time) int {
+	switch {
+	case t.IsInfinity():
+		return 1
+	case t.IsNegInfinity():
+		return -1
+	case c.Infinity != InfinityClamp:
+		return 0
+	}
+	if !t.Before(MaxTime) {
+		return 1
+	}
+	if !t.After(MinTime) {
+		return -1
+	}
+	return 0
+}
+
+// checkRange returns an error unless the finite t is in the timestamp range
+// of PostgreSQL; microseconds since 2000 overflow int64 outside of it.
+func checkRange(t timi.Time) error {
+	if t.Time.Before(minTimestamp) || !t.Time.Before(MaxTime.Time) {
+		return fmt.Errorf("timi/pgx: %v is outside the timestamp range of PostgreSQL", t)
+	}
+	return nil
+}
+
+// scanInfinity returns the time infinity (sign +1) or -infinity (sign -1)
+// scans as.
+func (c *Codec) scanInfinity(sign int) (timi.Time, error) {
+	switch c.Infinity {
+	case InfinityNull:
+		return timi.NilTime, nil
+	case InfinityClamp:
+		if sign < 0 {
+			return MinTime, nil
+		}
+		return MaxTime, nil
+	case InfinityValue:
+		if sign < 0 {
+			return timi.NegInfinity, nil
+		}
+		return timi.Infinity, nil
+	}
+	return timi.NilTime, ErrInfinity
+}
+
+func textLayout(oid uint32) string {
+	if oid == pgtype.TimestampOID {
+		return timestampFormat
+	}
+	return timestamptzFormat
+}
+
+type encodePlanBinary struct{ c *Codec }
+
+func (p encodePlanBinary) Encode(value any, buf []byte) ([]byte, error) {
+	t := value.(timi.Time)
+	if !t.Valid {
+		return nil, nil
+	}
+	var us int64
+	switch p.c.infinity(t) {
+	case 1:
+		us = infinityMicroseconds
+	case -1:
+		us = negInfinityMicroseconds
+	default:
+		if err := checkRange(t); err != nil {
+			return nil, err
+		}
+		us = (t.Time.Unix()-secFromUnixEpochToY2K)*1000000 + int64(t.Time.Nanosecond())/1000
+	}
+	return binary.BigEndian.AppendUint64(buf, uint64(us)), nil
+}
+
+type encodePlanText struct {
+	c      *Codec
+	layout string
+}
+
+func (p encodePlanText) Encode(value any, buf []byte) ([]byte, error) {
+	t := value.(timi.Time)
+	if !t.Valid {
+		return nil, nil
+	}
+	switch p.c.infinity(t) {
+	case 1:
+		return append(buf, "infinity"...), nil
+	case -1:
+		return append(buf, "-infinity"...), nil
+	}
+	if err := checkRange(t); err != nil {
+		return nil, err
+	}
+	tv := t.Time.UTC().Truncate(time.Microsecond)
+	// Year 0 is 1 BC. The year is written separately so that leap days of
+	// BC years are kept.
+	year := tv.Year()
+	if year <= 0 {
+		year = 1 - year
+	}
+	for n := 1000; n > 1 && year < n; n /= 10 {
+		buf = append(buf, '0')
+	}
+	buf = strconv.AppendInt(buf, int64(year), 10)
+	buf = tv.AppendFormat(buf, p.layout[len("2006"):])
+	if tv.Year() <= 0 {
+		buf = append(buf, " BC"...)
+	}
+	return buf, nil
+}
+
+type scanPlanBinary struct{ c *Codec }
+
+func (p scanPlanBinary) Scan(src []byte, dst any) error {
+	t := dst.(*timi.Time)
+	if src == nil {
+		*t = timi.NilTime
+		return nil
+	}
+	if len(src) != 8 {
+		return fmt.Errorf("timi/pgx: invalid length for timestamp: %d", len(src))
+	}
+	us := int64(binary.BigEndian.Uint64(src))
+	switch us {
+	case infinityMicroseconds, negInfinityMicroseconds:
+		sign := 1
+		if us == negInfinityMicroseconds {
+			sign = -1
+		}
+		v, err := p.c.scanInfinity(sign)
+		if err != nil {
+			return err
+		}
+		*t = v
+		return nil
+	}
+	*t = timi.Time{Time: time.Unix(secFromUnixEpochToY2K+us/1000000, us%1000000*1000).UTC(), Valid: true}
+	return nil
+}
+
+type scanPlanText struct {
+	c    *Codec
+	oid  uint32
+	next pgtype.ScanPlan
+}
+
+func (p *scanPlanText) Scan(src []byte, dst any) error {
+	t := dst.(*timi.Time)
+	if src == nil {
+		*t = timi.NilTime
+		return nil
+	}
+	var (
+		tv   time.Time
+		inf  pgtype.InfinityModifier
+		null bool
+	)
+	if p.oid == pgtype.TimestampOID {
+		var ts pgtype.Timestamp
+		if err := p.next.Scan(src, &ts); err != nil {
+			return err
+		}
+		tv, inf, null = ts.Time, ts.InfinityModifier, !ts.Valid
+	} else {
+		var ts pgtype.Timestamptz
+		if err := p.next.Scan(src, &ts); err != nil {
+			return err
+		}
+		tv, inf, null = ts.Time, ts.InfinityModifier, !ts.Valid
+	}
+	switch {
+	case null:
+		*t = timi.NilTime
+	case inf != pgtype.Finite:
+		v, err := p.c.scanInfinity(int(inf))
+		if err != nil {
+			return err
+		}
+		*t = v
+	default:
+		*t = timi.Time{Time: tv.UTC(), Valid: true}
+	}
+	return nil
+}
@@ -0,0 +1,397 @@
+package pgx
+
+import (
+	"bytes"
+	"errors"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/ieshan/timi"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+func newMap(inf Infinity) *pgtype.Map {
+	m := pgtype.NewMap()
+	RegisterWithInfinity(m, inf)
+	return m
+}
+
+var formats = []struct {
+	name string
+	code int16
+}{
+	{"Binary", pgtype.BinaryFormatCode},
+	{"Text", pgtype.TextFormatCode},
+}
+
+var oids = []struct {
+	name string
+	oid  uint32
+}{
+	{"timestamp", pgtype.TimestampOID},
+	{"timestamptz", pgtype.TimestamptzOID},
+}
+
+func TestCodec_RoundTrip(t *testing.T) {
+	m := newMap(InfinityError)
+	values := []timi.Time{
+		timi.Date(2024, 1, 15, 10, 30, 45, 123456000, time.UTC),
+		timi.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC),
+		timi.Date(1969, 12, 31, 23, 59, 59, 999999000, time.UTC),
+		timi.Date(1, 1, 1, 0, 0, 0, 0, time.UTC),
+		timi.Date(-43, 3, 15, 12, 0, 0, 0, time.UTC), // 44 BC
+		timi.NilTime,
+	}
+	for _, o := range oids {
+		for _, f := range formats {
+			t.Run(o.name+f.name, func(t *testing.T) {
+				for _, v := range values {
+					buf, err := m.Encode(o.oid, f.code, v, nil)
+					if err != nil {
+						t.Fatalf("Encode %v failed: %v", v, err)
+					}
+					if !v.Valid && buf != nil {
+						t.Fatalf("Expected NULL for %v, got %q", v, buf)
+					}
+					var got timi.Time
+					if err = m.Scan(o.oid, f.code, buf, &got); err != nil {
+						t.Fatalf("Scan %v failed: %v", v, err)
+					}
+					if got.Valid != v.Valid || !got.Equal(v) {
+						t.Fatalf("Expected %v, got %v", v, got)
+					}
+					if got.Valid && got.Time.Location() != time.UTC {
+						t.Fatalf("Expected UTC, got %v", got.Time.Location())
+					}
+				}
+			})
+		}
+	}
+}
+
+func TestCodec_MatchesPgtype(t *testing.T) {
+	m := newMap(InfinityError)
+	def := pgtype.NewMap()
+	ti := timi.Date(2024, 1, 15, 10, 30, 45, 123456789, time.UTC)
+	for _, o := range oids {
+		for _, f := range formats {
+			got, err := m.Encode(o.oid, f.code, ti, nil)
+			if err != nil {
+				t.Fatalf("%s %s: Encode failed: %v", o.name, f.name, err)
+			}
+			expected, err := def.Encode(o.oid, f.code, ti.Time, nil)
+			if err != nil {
+				t.Fatalf("%s %s: pgtype Encode failed: %v", o.name, f.name, err)
+			}
+			if !bytes.Equal(got, expected) {
+				t.Fatalf("%s %s: expected %q, got %q", o.name, f.name, expected, got)
+			}
+
+			// Sub-microsecond precision is truncated
+			var scanned timi.Time
+			if err = m.Scan(o.oid, f.code, got, &scanned); err != nil {
+				t.Fatalf("%s %s: Scan failed: %v", o.name, f.name, err)
+			}
+			if !scanned.Equal(ti.Truncate(time.Microsecond)) {
+				t.Fatalf("%s %s: expected %v, got %v", o.name, f.name, ti.Truncate(time.Microsecond), scanned)
+			}
+		}
+	}
+}
+
+func TestCodec_ScanText(t *testing.T) {
+	m := newMap(InfinityError)
+	expected := timi.Date(2024, 1, 15, 10, 30, 45, 123456000, time.UTC)
+	testCases := []struct {
+		oid  uint32
+		text string
+	}{
+		{pgtype.TimestamptzOID, "2024-01-15 10:30:45.123456+00"},
+		{pgtype.TimestamptzOID, "2024-01-15 16:00:45.123456+05:30"},
+		{pgtype.TimestamptzOID, "2024-01-15 05:30:45.123456-05"},
+		{pgtype.TimestampOID, "2024-01-15 10:30:45.123456"},
+	}
+	for _, tc := range testCases {
+		var got timi.Time
+		if err := m.Scan(tc.oid, pgtype.TextFormatCode, []byte(tc.text), &got); err != nil {
+			t.Fatalf("%s: Scan failed: %v", tc.text, err)
+		}
+		if !got.Equal(expected) {
+			t.Fatalf("%s: expected %v, got %v", tc.text, expected, got)
+		}
+	}
+}
+
+func TestCodec_Infinity(t *testing.T) {
+	testCases := []struct {
+		name     string
+		inf      Infinity
+		pos, neg timi.Time
+		err      error
+	}{
+		{"Error", InfinityError, timi.NilTime, timi.NilTime, ErrInfinity},
+		{"Null", InfinityNull, timi.NilTime, timi.NilTime, nil},
+		{"Clamp", InfinityClamp, MaxTime, MinTime, nil},
+		{"Value", InfinityValue, timi.Infinity, timi.NegInfinity, nil},
+	}
+	def := pgtype.NewMap()
+	for _, tc := range testCases {
+		m := newMap(tc.inf)
+		for _, o := range oids {
+			for _, f := range formats {
+				for _, v := range []struct {
+					mod      pgtype.InfinityModifier
+					expected timi.Time
+				}{{pgtype.Infinity, tc.pos}, {pgtype.NegativeInfinity, tc.neg}} {
+					var value any = pgtype.Timestamptz{InfinityModifier: v.mod, Valid: true}
+					if o.oid == pgtype.TimestampOID {
+						value = pgtype.Timestamp{InfinityModifier: v.mod, Valid: true}
+					}
+					src, err := def.Encode(o.oid, f.code, value, nil)
+					if err != nil {
+						t.Fatalf("pgtype Encode failed: %v", err)
+					}
+					var got timi.Time
+					err = m.Scan(o.oid, f.code, src, &got)
+					if !errors.Is(err, tc.err) {
+						t.Fatalf("%s %s %s %s: expected error %v, got %v", tc.name, o.name, f.name, src, tc.err, err)
+					}
+					if err == nil && (got.Valid != v.expected.Valid || !got.Equal(v.expected)) {
+						t.Fatalf("%s %s %s %s: expected %v, got %v", tc.name, o.name, f.name, src, v.expected, got)
+					}
+				}
+			}
+		}
+	}
+}
+
+func TestCodec_EncodeInfinity(t *testing.T) {
+	testCases := []struct {
+		name     string
+		inf      Infinity
+		value    timi.Time
+		expected string
+	}{
+		{"ClampMax", InfinityClamp, MaxTime, "infinity"},
+		{"ClampBeyondMax", InfinityClamp, MaxTime.Add(time.Hour), "infinity"},
+		{"ClampMin", InfinityClamp, MinTime, "-infinity"},
+		{"ClampBeyondMin", InfinityClamp, MinTime.Add(-time.Hour), "-infinity"},
+		{"ClampFinite", InfinityClamp, MaxTime.Add(-time.Microsecond), "294276-12-31 23:59:59.999999Z"},
+		{"Error", InfinityError, MaxTime.Add(-time.Microsecond), "294276-12-31 23:59:59.999999Z"},
+		{"ErrorSentinel", InfinityError, timi.Infinity, "infinity"},
+		{"NullSentinel", InfinityNull, timi.NegInfinity, "-infinity"},
+		{"ValueSentinel", InfinityValue, timi.Infinity, "infinity"},
+	}
+	for _, tc := range testCases {
+		m := newMap(tc.inf)
+		text, err := m.Encode(pgtype.TimestamptzOID, pgtype.TextFormatCode, tc.value, nil)
+		if err != nil {
+			t.Fatalf("%s: Encode failed: %v", tc.name, err)
+		}
+		if string(text) != tc.expected {
+			t.Fatalf("%s: expected %s, got %s", tc.name, tc.expected, text)
+		}
+
+		bin, err := m.Encode(pgtype.TimestamptzOID, pgtype.BinaryFormatCode, tc.value, nil)
+		if err != nil {
+			t.Fatalf("%s: Encode failed: %v", tc.name, err)
+		}
+		var ts pgtype.Timestamptz
+		if err = pgtype.NewMap().Scan(pgtype.TimestamptzOID, pgtype.BinaryFormatCode, bin, &ts); err != nil {
+			t.Fatalf("%s: pgtype Scan failed: %v", tc.name, err)
+		}
+		if tc.expected == "infinity" && ts.InfinityModifier != pgtype.Infinity ||
+			tc.expected == "-infinity" && ts.InfinityModifier != pgtype.NegativeInfinity {
+			t.Fatalf("%s: expected %s, got %v", tc.name, tc.expected, ts.InfinityModifier)
+		}
+	}
+}
+
+func TestCodec_EncodeOutOfRange(t *testing.T) {
+	testCases := []struct {
+		name  string
+		inf   Infinity
+		value timi.Time
+	}{
+		{"AfterMax", InfinityError, timi.Date(294277, 6, 1, 0, 0, 0, 0, time.UTC)},
+		{"Unix", InfinityError, timi.Time{Time: time.Unix(math.MaxInt64/100000+1, 0), Valid: true}},
+		{"MaxTime", InfinityNull, MaxTime},
+		{"BeforeMin", InfinityValue, timi.Date(-4713, 11, 23, 23, 59, 59, 0, time.UTC)},
+	}
+	for _, tc := range testCases {
+		m := newMap(tc.inf)
+		for _, f := range formats {
+			if _, err := m.Encode(pgtype.TimestamptzOID, f.code, tc.value, nil); err == nil {
+				t.Fatalf("%s/%s: expected an error for %v", tc.name, f.name, tc.value)
+			}
+		}
+	}
+}
+
+func TestCodec_EncodeRangeEnds(t *testing.T) {
+	m := newMap(InfinityError)
+	// Microseconds since 1970 overflow int64 for this time, but not
+	// microseconds since 2000.
+	values := []timi.Time{
+		{Time: time.Unix(math.MaxInt64/1000000+1, 0).UTC(), Valid: true},
+		MaxTime.Add(-time.Microsecond),
+		timi.Date(-4713, 11, 24, 0, 0, 0, 0, time.UTC),
+	}
+	for _, value := range values {
+		bin, err := m.Encode(pgtype.TimestamptzOID, pgtype.BinaryFormatCode, value, nil)
+		if err != nil {
+			t.Fatalf("Encode %v failed: %v", value, err)
+		}
+		var got timi.Time
+		if err = m.Scan(pgtype.TimestamptzOID, pgtype.BinaryFormatCode, bin, &got); err != nil {
+			t.Fatalf("Scan %v failed: %v", value, err)
+		}
+		if !got.Equal(value) {
+			t.Fatalf("Expected %v, got %v", value, got)
+		}
+	}
+}
+
+func TestCodec_EncodeTextBC(t *testing.T) {
+	m := newMap(InfinityError)
+	testCases := []struct {
+		oid      uint32
+		value    timi.Time
+		expected string
+	}{
+		{pgtype.TimestamptzOID, timi.Date(-43, 3, 15, 12, 0, 0, 0, time.UTC), "0044-03-15 12:00:00Z BC"},
+		{pgtype.TimestampOID, timi.Date(0, 2, 29, 0, 0, 0, 0, time.UTC), "0001-02-29 00:00:00 BC"},
+		{pgtype.TimestampOID, timi.Date(12345, 6, 7, 8, 9, 10, 0, time.UTC), "12345-06-07 08:09:10"},
+	}
+	for _, tc := range testCases {
+		text, err := m.Encode(tc.oid, pgtype.TextFormatCode, tc.value, nil)
+		if err != nil {
+			t.Fatalf("Encode %v failed: %v", tc.value, err)
+		}
+		if string(text) != tc.expected {
+			t.Fatalf("Expected %s, got %s", tc.expected, text)
+		}
+	}
+}
+
+func TestCodec_Arrays(t *testing.T) {
+	m := newMap(InfinityClamp)
+	values := []timi.Time{
+		timi.Date(2024, 1, 15, 10, 30, 45, 123456000, time.UTC),
+		timi.NilTime,
+		MaxTime,
+		MinTime,
+	}
+	for _, oid := range []uint32{pgtype.TimestampArrayOID, pgtype.TimestamptzArrayOID} {
+		for _, f := range formats {
+			buf, err := m.Encode(oid, f.code, values, nil)
+			if err != nil {
+				t.Fatalf("%d %s: Encode failed: %v", oid, f.name, err)
+			}
+			var got []timi.Time
+			if err = m.Scan(oid, f.code, buf, &got); err != nil {
+				t.Fatalf("%d %s: Scan failed: %v", oid, f.name, err)
+			}
+			if len(got) != len(values) {
+				t.Fatalf("%d %s: expected %d elements, got %d", oid, f.name, len(values), len(got))
+			}
+			for i := range values {
+				if got[i].Valid != values[i].Valid || !got[i].Equal(values[i]) {
+					t.Fatalf("%d %s: element %d: expected %v, got %v", oid, f.name, i, values[i], got[i])
+				}
+			}
+		}
+	}
+
+	text, err := m.Encode(pgtype.TimestamptzArrayOID, pgtype.TextFormatCode, values[:3], nil)
+	if err != nil {
+		t.Fatalf("Encode failed: %v", err)
+	}
+	expected := `{2024-01-15 10:30:45.123456Z,NULL,infinity}`
+	if string(text) != expected {
+		t.Fatalf("Expected %s, got %s", expected, text)
+	}
+}
+
+func TestCodec_OtherValues(t *testing.T) {
+	m := newMap(InfinityError)
+	ti := time.Date(2024, 1, 15, 10, 30, 45, 123456000, time.UTC)
+
+	// time.Time and pointers still go through the wrapped codec
+	buf, err := m.Encode(pgtype.TimestamptzOID, pgtype.BinaryFormatCode, ti, nil)
+	if err != nil {
+		t.Fatalf("Encode failed: %v", err)
+	}
+	var got time.Time
+	if err = m.Scan(pgtype.TimestamptzOID, pgtype.BinaryFormatCode, buf, &got); err != nil {
+		t.Fatalf("Scan failed: %v", err)
+	}
+	if !got.Equal(ti) {
+		t.Fatalf("Expected %v, got %v", ti, got)
+	}
+
+	var ptr *timi.Time
+	if err = m.Scan(pgtype.TimestamptzOID, pgtype.BinaryFormatCode, buf, &ptr); err != nil {
+		t.Fatalf("Scan failed: %v", err)
+	}
+	if ptr == nil || !ptr.Time.Equal(ti) {
+		t.Fatalf("Expected %v, got %v", ti, ptr)
+	}
+	if buf, err = m.Encode(pgtype.TimestamptzOID, pgtype.BinaryFormatCode, (*timi.Time)(nil), nil); err != nil || buf != nil {
+		t.Fatalf("Expected NULL, got %q, %v", buf, err)
+	}
+
+	// Parameters of unknown type are sent as timestamptz
+	if dt, ok := m.TypeForValue(timi.Now()); !ok || dt.OID != pgtype.TimestamptzOID {
+		t.Fatalf("Expected timestamptz, got %v", dt)
+	}
+	if dt, ok := m.TypeForValue([]timi.Time{}); !ok || dt.OID != pgtype.TimestamptzArrayOID {
+		t.Fatalf("Expected timestamptz[], got %v", dt)
+	}
+}
+
+func TestRegister_KeepsCodecSettings(t *testing.T) {
+	m := pgtype.NewMap()
+	ny, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Skipf("time zone database unavailable: %v", err)
+	}
+	m.RegisterType(&pgtype.Type{Name: "timestamptz", OID: pgtype.TimestamptzOID, Codec: &pgtype.TimestamptzCodec{ScanLocation: ny}})
+	Register(m)
+	Register(m) // registering twice does not wrap the codec again
+
+	dt, _ := m.TypeForOID(pgtype.TimestamptzOID)
+	c, ok := dt.Codec.(*Codec)
+	if !ok {
+		t.Fatalf("Expected *Codec, got %T", dt.Codec)
+	}
+	if next, ok := c.Next.(*pgtype.TimestamptzCodec); !ok || next.ScanLocation != ny {
+		t.Fatalf("Expected the registered TimestamptzCodec, got %#v", c.Next)
+	}
+
+	// Register scans the infinities as the sentinels, like timi.Time.Scan
+	for _, f := range formats {
+		for _, expected := range []timi.Time{timi.Infinity, timi.NegInfinity} {
+			src, err := m.Encode(pgtype.TimestamptzOID, f.code, expected, nil)
+			if err != nil {
+				t.Fatalf("%s: Encode %v failed: %v", f.name, expected, err)
+			}
+			var got timi.Time
+			if err = m.Scan(pgtype.TimestamptzOID, f.code, src, &got); err != nil || got != expected {
+				t.Fatalf("%s: expected %v, got %v, %v", f.name, expected, got, err)
+			}
+		}
+	}
+}
+
+func BenchmarkCodec_ScanBinary(b *testing.B) {
+	m := newMap(InfinityError)
+	buf, _ := m.Encode(pgtype.TimestamptzOID, pgtype.BinaryFormatCode, timi.Now(), nil)
+	plan := m.PlanScan(pgtype.TimestamptzOID, pgtype.BinaryFormatCode, &timi.Time{})
+	var t timi.Time
+	b.ReportAllocs()
+	for b.Loop() {
+		_ = plan.Scan(buf, &t)
+	}
+}
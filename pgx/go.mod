@@ -0,0 +1,11 @@
+module github.com/ieshan/timi/pgx
+
+go 1.25.0
+
+require (
+	github.com/ieshan/timi v0.0.0
+	github.com/jackc/pgx/v5 v5.7.5
+)
+
+// Use local timi package
+replace github.com/ieshan/timi => ../
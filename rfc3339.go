@@ -0,0 +1,90 @@
+package timi
+
+import "time"
+
+// parseRFC3339 parses the common RFC 3339 forms used on the wire:
+// "2006-01-02T15:04:05" followed by an optional fraction of one to nine
+// digits and either "Z" or a "+hh:mm"/"-hh:mm" offset. The result is in UTC,
+// so no time.Location is allocated for offsets.
+//
+// It reports false for any input outside of that grammar, including
+// out-of-range fields. Callers then fall back to the time package, which
+// accepts the remaining forms and produces the error message.
+func parseRFC3339(b []byte) (time.Time, bool) {
+	if len(b) < len("2006-01-02T15:04:05Z") ||
+		b[4] != '-' || b[7] != '-' || b[10] != 'T' || b[13] != ':' || b[16] != ':' {
+		return time.Time{}, false
+	}
+	hi, ok1 := parseDigits2(b[0:2])
+	lo, ok2 := parseDigits2(b[2:4])
+	month, ok3 := parseDigits2(b[5:7])
+	day, ok4 := parseDigits2(b[8:10])
+	hour, ok5 := parseDigits2(b[11:13])
+	minute, ok6 := parseDigits2(b[14:16])
+	sec, ok7 := parseDigits2(b[17:19])
+	if !(ok1 && ok2 && ok3 && ok4 && ok5 && ok6 && ok7) {
+		return time.Time{}, false
+	}
+	year := hi*100 + lo
+	if month < 1 || month > 12 || day < 1 || day > daysIn(time.Month(month), year) ||
+		hour > 23 || minute > 59 || sec > 59 {
+		return time.Time{}, false
+	}
+	b = b[19:]
+
+	nsec := 0
+	if b[0] == '.' {
+		i := 1
+		for ; i < len(b) && '0' <= b[i] && b[i] <= '9'; i++ {
+			if i > 9 {
+				return time.Time{}, false
+			}
+			nsec = nsec*10 + int(b[i]-'0')
+		}
+		if i == 1 {
+			return time.Time{}, false
+		}
+		for range 10 - i {
+			nsec *= 10
+		}
+		b = b[i:]
+	}
+
+	offset := 0
+	switch {
+	case len(b) == 1 && b[0] == 'Z':
+	case len(b) == len("+07:00") && (b[0] == '+' || b[0] == '-') && b[3] == ':':
+		zoneHour, ok1 := parseDigits2(b[1:3])
+		zoneMinute, ok2 := parseDigits2(b[4:6])
+		if !ok1 || !ok2 || zoneHour > 23 || zoneMinute > 59 {
+			return time.Time{}, false
+		}
+		offset = (zoneHour*60 + zoneMinute) * 60
+		if b[0] == '-' {
+			offset = -offset
+		}
+	default:
+		return time.Time{}, false
+	}
+	return time.Date(year, time.Month(month), day, hour, minute, sec-offset, nsec, time.UTC), true
+}
+
+func parseDigits2(b []byte) (int, bool) {
+	if b[0] < '0' || b[0] > '9' || b[1] < '0' || b[1] > '9' {
+		return 0, false
+	}
+	return int(b[0]-'0')*10 + int(b[1]-'0'), true
+}
+
+func daysIn(month time.Month, year int) int {
+	switch month {
+	case time.February:
+		if year%4 == 0 && (year%100 != 0 || year%400 == 0) {
+			return 29
+		}
+		return 28
+	case time.April, time.June, time.September, time.November:
+		return 30
+	}
+	return 31
+}
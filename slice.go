@@ -0,0 +1,242 @@
+package timi
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Time Arrays
+// Slice and JSONSlice are lists of times, with null elements, stored in a
+// single column. Slice is written as a PostgreSQL array literal, for
+// timestamp[] and timestamptz[] columns, and JSONSlice as a JSON array, for
+// MySQL JSON and SQLite TEXT columns. Scan of both reads either form, and a
+// NULL column scans as a nil slice.
+
+// Slice is a list of times stored as a PostgreSQL array, such as
+// {"2024-01-15 10:30:45+00:00",NULL,infinity}.
+type Slice []Time
+
+// JSONSlice is a list of times stored as a JSON array, such as
+// ["2024-01-15T10:30:45Z",null,"infinity"].
+type JSONSlice []Time
+
+// pgArrayLayout is the layout of Slice elements. The year is written
+// separately, so that BC years are kept.
+const pgArrayLayout = "-01-02 15:04:05.999999999-07:00"
+
+// pgTimestampLayouts are the text formats of timestamp and timestamptz, with
+// the offsets PostgreSQL writes for the session time zone.
+var pgTimestampLayouts = []string{
+	"2006-01-02 15:04:05.999999999Z07",
+	"2006-01-02 15:04:05.999999999Z07:00",
+	"2006-01-02 15:04:05.999999999Z07:00:00",
+	"2006-01-02 15:04:05.999999999",
+	"2006-01-02T15:04:05.999999999Z07:00",
+	"2006-01-02",
+}
+
+var errMultiDimArray = errors.New("timi: multidimensional arrays are not supported")
+
+// Value implements the driver.Valuer interface.
+func (s Slice) Value() (driver.Value, error) {
+	if s == nil {
+		return nil, nil
+	}
+	b := make([]byte, 0, 2+len(s)*34)
+	b = append(b, '{')
+	for i, t := range s {
+		if i > 0 {
+			b = append(b, ',')
+		}
+		switch {
+		case !t.Valid:
+			b = append(b, "NULL"...)
+		case t.IsInfinite():
+			b = append(b, t.infinityText()...)
+		default:
+			b = append(b, '"')
+			b = appendPgTimestamp(b, t.Time.UTC())
+			b = append(b, '"')
+		}
+	}
+	return string(append(b, '}')), nil
+}
+
+// Scan implements the sql.Scanner interface.
+func (s *Slice) Scan(value interface{}) error {
+	return scanSlice((*[]Time)(s), value)
+}
+
+// Value implements the driver.Valuer interface.
+func (s JSONSlice) Value() (driver.Value, error) {
+	if s == nil {
+		return nil, nil
+	}
+	b, err := json.Marshal([]Time(s))
+	if err != nil {
+		return nil, err
+	}
+	return string(b), nil
+}
+
+// Scan implements the sql.Scanner interface.
+func (s *JSONSlice) Scan(value interface{}) error {
+	return scanSlice((*[]Time)(s), value)
+}
+
+// scanSlice scans a PostgreSQL array literal or a JSON array into dst.
+func scanSlice(dst *[]Time, value interface{}) error {
+	var src string
+	switch v := value.(type) {
+	case nil:
+		*dst = nil
+		return nil
+	case string:
+		src = v
+	case []byte:
+		src = string(v)
+	default:
+		return fmt.Errorf("timi: cannot scan %T into a time array", value)
+	}
+	src = strings.TrimSpace(src)
+	if isPgArray(src) {
+		ts, err := parsePgArray(src)
+		if err != nil {
+			return err
+		}
+		*dst = ts
+		return nil
+	}
+	if strings.HasPrefix(src, "[") || src == "null" {
+		var ts []Time
+		if err := json.Unmarshal([]byte(src), &ts); err != nil {
+			return err
+		}
+		*dst = ts
+		return nil
+	}
+	return fmt.Errorf("timi: cannot parse %q as a time array", src)
+}
+
+// isPgArray reports whether s is a PostgreSQL array literal, optionally with
+// dimension decoration such as [0:1]={...}.
+func isPgArray(s string) bool {
+	if strings.HasPrefix(s, "{") {
+		return true
+	}
+	if strings.HasPrefix(s, "[") {
+		if i := strings.Index(s, "]={"); i > 0 && strings.IndexByte(s[:i], '"') < 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// parsePgArray parses a one-dimensional PostgreSQL array literal.
+func parsePgArray(s string) ([]Time, error) {
+	if i := strings.Index(s, "={"); strings.HasPrefix(s, "[") && i > 0 {
+		s = s[i+1:]
+	}
+	if len(s) < 2 || s[len(s)-1] != '}' {
+		return nil, fmt.Errorf("timi: invalid array literal %q", s)
+	}
+	body := s[1 : len(s)-1]
+	ts := []Time{}
+	if strings.TrimSpace(body) == "" {
+		return ts, nil
+	}
+	for i := 0; ; {
+		for i < len(body) && body[i] == ' ' {
+			i++
+		}
+		var (
+			elem   string
+			quoted bool
+		)
+		switch {
+		case i < len(body) && body[i] == '{':
+			return nil, errMultiDimArray
+		case i < len(body) && body[i] == '"':
+			var b strings.Builder
+			for i++; i < len(body) && body[i] != '"'; i++ {
+				if body[i] == '\\' && i+1 < len(body) {
+					i++
+				}
+				b.WriteByte(body[i])
+			}
+			if i == len(body) {
+				return nil, fmt.Errorf("timi: unterminated quote in array literal %q", s)
+			}
+			i++
+			elem, quoted = b.String(), true
+		default:
+			j := strings.IndexByte(body[i:], ',')
+			if j < 0 {
+				j = len(body) - i
+			}
+			elem = strings.TrimSpace(body[i : i+j])
+			i += j
+		}
+		t, err := parsePgElement(elem, quoted)
+		if err != nil {
+			return nil, err
+		}
+		ts = append(ts, t)
+
+		for i < len(body) && body[i] == ' ' {
+			i++
+		}
+		if i == len(body) {
+			return ts, nil
+		}
+		if body[i] != ',' {
+			return nil, fmt.Errorf("timi: invalid array literal %q", s)
+		}
+		i++
+	}
+}
+
+// parsePgElement parses an array element in the text format of timestamp or
+// timestamptz. An unquoted NULL is a null time.
+func parsePgElement(s string, quoted bool) (Time, error) {
+	if !quoted && strings.EqualFold(s, "NULL") {
+		return NilTime, nil
+	}
+	if inf, ok := parseInfinityText(s); ok {
+		return inf, nil
+	}
+	bc := strings.HasSuffix(s, " BC")
+	s = strings.TrimSuffix(s, " BC")
+	for _, layout := range pgTimestampLayouts {
+		if tv, err := time.Parse(layout, s); err == nil {
+			if bc {
+				// Year 1 BC is year 0
+				tv = time.Date(1-tv.Year(), tv.Month(), tv.Day(), tv.Hour(), tv.Minute(), tv.Second(), tv.Nanosecond(), tv.Location())
+			}
+			return Time{Time: tv.UTC(), Valid: true}, nil
+		}
+	}
+	return NilTime, fmt.Errorf("timi: cannot parse array element %q", s)
+}
+
+// appendPgTimestamp appends tv in the text format of timestamptz.
+func appendPgTimestamp(b []byte, tv time.Time) []byte {
+	year := tv.Year()
+	if year <= 0 {
+		year = 1 - year
+	}
+	for n := 1000; n > 1 && year < n; n /= 10 {
+		b = append(b, '0')
+	}
+	b = strconv.AppendInt(b, int64(year), 10)
+	b = tv.AppendFormat(b, pgArrayLayout)
+	if tv.Year() <= 0 {
+		b = append(b, " BC"...)
+	}
+	return b
+}
@@ -0,0 +1,241 @@
+// Package sqlfilter builds SQL predicates over nullable timi.Time columns.
+//
+// A null bound means unbounded on that side. Comparisons never match NULL
+// columns, so range predicates exclude rows where the column is null, and
+// Overlaps treats a null start or end column as an open end. Predicates
+// render with the placeholder style of the dialect and pass bounds as
+// timi.Time arguments, so they work with database/sql and with GORM:
+//
+//	p := sqlfilter.Or(
+//		sqlfilter.Between("created_at", from, to, sqlfilter.ClosedOpen),
+//		sqlfilter.IsNull("ended_at"),
+//	)
+//	query, args := p.Build(sqlfilter.Dollar, 1)
+//	rows, err := db.Query("SELECT id FROM events WHERE "+query, args...)
+//
+//	db.Where(p.String(), p.Args()...).Find(&events)
+//
+// Column names are written as given, without quoting; never pass user input
+// as a column.
+package sqlfilter
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/ieshan/timi"
+)
+
+// Placeholder is the bind parameter style of a SQL dialect.
+type Placeholder int
+
+const (
+	// Question writes ?, for MySQL, MariaDB, SQLite and GORM.
+	Question Placeholder = iota
+	// Dollar writes $1, $2, ..., for PostgreSQL.
+	Dollar
+	// AtP writes @p1, @p2, ..., for SQL Server.
+	AtP
+)
+
+// PlaceholderOf returns the placeholder style of a database/sql driver or
+// GORM dialector name, such as "pgx", "postgres", "sqlserver" or "mysql".
+// Unknown names use Question.
+func PlaceholderOf(name string) Placeholder {
+	switch name {
+	case "postgres", "pgx", "pgx/v5", "cloudsqlpostgres":
+		return Dollar
+	case "sqlserver", "mssql", "azuresql":
+		return AtP
+	}
+	return Question
+}
+
+// Bounds selects which ends of a Between range are included.
+type Bounds int
+
+const (
+	// Closed includes both ends: a <= column <= b.
+	Closed Bounds = iota
+	// ClosedOpen includes the lower end only, the half-open interval
+	// a <= column < b.
+	ClosedOpen
+	// OpenClosed includes the upper end only: a < column <= b.
+	OpenClosed
+	// Open excludes both ends: a < column < b.
+	Open
+)
+
+// Predicate is a SQL boolean expression and its arguments. The zero
+// Predicate is always true.
+type Predicate struct {
+	// parts are the SQL around the arguments, one more than args.
+	parts []string
+	args  []any
+	// sep is the top-level operator, " AND " or " OR ", of a compound
+	// predicate.
+	sep string
+}
+
+func expr(sql string) Predicate {
+	return Predicate{parts: []string{sql}}
+}
+
+func compare(column, op string, t timi.Time) Predicate {
+	return Predicate{parts: []string{column + " " + op + " ", ""}, args: []any{t}}
+}
+
+// IsNull matches rows where column is null.
+func IsNull(column string) Predicate {
+	return expr(column + " IS NULL")
+}
+
+// NotNull matches rows where column is not null.
+func NotNull(column string) Predicate {
+	return expr(column + " IS NOT NULL")
+}
+
+// Before matches rows where column is before t.
+// A null t matches every non-null column.
+func Before(column string, t timi.Time) Predicate {
+	return Between(column, timi.NilTime, t, Open)
+}
+
+// After matches rows where column is after t.
+// A null t matches every non-null column.
+func After(column string, t timi.Time) Predicate {
+	return Between(column, t, timi.NilTime, Open)
+}
+
+// Between matches rows where column lies between a and b, with the ends
+// included as selected by bounds. A null a or b leaves that side unbounded;
+// with both null, Between matches every non-null column.
+func Between(column string, a, b timi.Time, bounds Bounds) Predicate {
+	var conds []Predicate
+	if a.Valid {
+		op := ">"
+		if bounds == Closed || bounds == ClosedOpen {
+			op = ">="
+		}
+		conds = append(conds, compare(column, op, a))
+	}
+	if b.Valid {
+		op := "<"
+		if bounds == Closed || bounds == OpenClosed {
+			op = "<="
+		}
+		conds = append(conds, compare(column, op, b))
+	}
+	if conds == nil {
+		return NotNull(column)
+	}
+	return And(conds...)
+}
+
+// Overlaps matches rows whose half-open period [start, end) overlaps the
+// half-open interval [from, to). A null start column is unbounded in the
+// past and a null end column, such as an ongoing period, in the future; a
+// null from or to leaves the interval unbounded on that side.
+func Overlaps(start, end string, from, to timi.Time) Predicate {
+	var conds []Predicate
+	if to.Valid {
+		conds = append(conds, Or(IsNull(start), compare(start, "<", to)))
+	}
+	if from.Valid {
+		conds = append(conds, Or(IsNull(end), compare(end, ">", from)))
+	}
+	return And(conds...)
+}
+
+// And matches rows that match every p. With no predicates, it is true.
+func And(ps ...Predicate) Predicate {
+	return join(" AND ", "1 = 1", ps)
+}
+
+// Or matches rows that match any p. With no predicates, it is false.
+func Or(ps ...Predicate) Predicate {
+	return join(" OR ", "1 = 0", ps)
+}
+
+func join(sep, empty string, ps []Predicate) Predicate {
+	switch len(ps) {
+	case 0:
+		return expr(empty)
+	case 1:
+		return ps[0]
+	}
+	out := expr("")
+	for i, p := range ps {
+		if i > 0 {
+			out = out.concat(expr(sep))
+		}
+		if p.sep == "" || p.sep == sep {
+			out = out.concat(p)
+		} else {
+			out = out.concat(expr("(")).concat(p).concat(expr(")"))
+		}
+	}
+	out.sep = sep
+	return out
+}
+
+// norm returns p, or a true expression for the zero Predicate.
+func (p Predicate) norm() Predicate {
+	if len(p.parts) == 0 {
+		return expr("1 = 1")
+	}
+	return p
+}
+
+// concat returns p followed by q.
+func (p Predicate) concat(q Predicate) Predicate {
+	p, q = p.norm(), q.norm()
+	parts := make([]string, 0, len(p.parts)+len(q.parts)-1)
+	parts = append(parts, p.parts[:len(p.parts)-1]...)
+	parts = append(parts, p.parts[len(p.parts)-1]+q.parts[0])
+	parts = append(parts, q.parts[1:]...)
+	args := make([]any, 0, len(p.args)+len(q.args))
+	args = append(append(args, p.args...), q.args...)
+	return Predicate{parts: parts, args: args}
+}
+
+// Build returns the SQL of p with placeholders in the style ph, numbered
+// from first for Dollar and AtP, and its arguments. Pass the number of
+// arguments that come before p in the query plus one as first.
+func (p Predicate) Build(ph Placeholder, first int) (string, []any) {
+	p = p.norm()
+	var b strings.Builder
+	for i, part := range p.parts {
+		b.WriteString(part)
+		if i == len(p.args) {
+			break
+		}
+		switch ph {
+		case Dollar:
+			b.WriteByte('$')
+			b.WriteString(strconv.Itoa(first + i))
+		case AtP:
+			b.WriteString("@p")
+			b.WriteString(strconv.Itoa(first + i))
+		default:
+			b.WriteByte('?')
+		}
+	}
+	return b.String(), p.Args()
+}
+
+// SQL returns the SQL of p with placeholders in the style ph, numbered from 1.
+func (p Predicate) SQL(ph Placeholder) string {
+	s, _ := p.Build(ph, 1)
+	return s
+}
+
+// String returns the SQL of p with ? placeholders, as GORM Where expects.
+func (p Predicate) String() string {
+	return p.SQL(Question)
+}
+
+// Args returns the arguments of p, in placeholder order.
+func (p Predicate) Args() []any {
+	return append([]any(nil), p.args...)
+}
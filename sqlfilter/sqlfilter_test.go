@@ -0,0 +1,111 @@
+package sqlfilter
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/ieshan/timi"
+)
+
+var (
+	from = timi.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	to   = timi.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC)
+)
+
+func TestPredicates(t *testing.T) {
+	testCases := []struct {
+		name string
+		p    Predicate
+		sql  string
+		args []any
+	}{
+		{"IsNull", IsNull("ended_at"), "ended_at IS NULL", nil},
+		{"NotNull", NotNull("ended_at"), "ended_at IS NOT NULL", nil},
+		{"Before", Before("created_at", to), "created_at < ?", []any{to}},
+		{"After", After("created_at", from), "created_at > ?", []any{from}},
+		{"BeforeNull", Before("created_at", timi.NilTime), "created_at IS NOT NULL", nil},
+		{"Closed", Between("created_at", from, to, Closed), "created_at >= ? AND created_at <= ?", []any{from, to}},
+		{"ClosedOpen", Between("created_at", from, to, ClosedOpen), "created_at >= ? AND created_at < ?", []any{from, to}},
+		{"OpenClosed", Between("created_at", from, to, OpenClosed), "created_at > ? AND created_at <= ?", []any{from, to}},
+		{"Open", Between("created_at", from, to, Open), "created_at > ? AND created_at < ?", []any{from, to}},
+		{"LowerOnly", Between("created_at", from, timi.NilTime, ClosedOpen), "created_at >= ?", []any{from}},
+		{"UpperOnly", Between("created_at", timi.NilTime, to, ClosedOpen), "created_at < ?", []any{to}},
+		{"Unbounded", Between("created_at", timi.NilTime, timi.NilTime, ClosedOpen), "created_at IS NOT NULL", nil},
+		{"Overlaps", Overlaps("started_at", "ended_at", from, to),
+			"(started_at IS NULL OR started_at < ?) AND (ended_at IS NULL OR ended_at > ?)", []any{to, from}},
+		{"OverlapsFrom", Overlaps("started_at", "ended_at", from, timi.NilTime), "ended_at IS NULL OR ended_at > ?", []any{from}},
+		{"OverlapsAll", Overlaps("started_at", "ended_at", timi.NilTime, timi.NilTime), "1 = 1", nil},
+		{"WindowOrNull", Or(Between("created_at", from, to, ClosedOpen), IsNull("ended_at")),
+			"(created_at >= ? AND created_at < ?) OR ended_at IS NULL", []any{from, to}},
+		{"Flattened", And(And(IsNull("a"), IsNull("b")), IsNull("c")), "a IS NULL AND b IS NULL AND c IS NULL", nil},
+		{"EmptyAnd", And(), "1 = 1", nil},
+		{"EmptyOr", Or(), "1 = 0", nil},
+		{"Zero", And(Predicate{}, IsNull("a")), "1 = 1 AND a IS NULL", nil},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if sql := tc.p.String(); sql != tc.sql {
+				t.Fatalf("Expected %s, got %s", tc.sql, sql)
+			}
+			if args := tc.p.Args(); !reflect.DeepEqual(args, tc.args) {
+				t.Fatalf("Expected %v, got %v", tc.args, args)
+			}
+		})
+	}
+}
+
+func TestPredicate_Build(t *testing.T) {
+	p := Or(Between("created_at", from, to, ClosedOpen), IsNull("ended_at"))
+	testCases := []struct {
+		ph    Placeholder
+		first int
+		sql   string
+	}{
+		{Question, 1, "(created_at >= ? AND created_at < ?) OR ended_at IS NULL"},
+		{Dollar, 1, "(created_at >= $1 AND created_at < $2) OR ended_at IS NULL"},
+		{Dollar, 3, "(created_at >= $3 AND created_at < $4) OR ended_at IS NULL"},
+		{AtP, 2, "(created_at >= @p2 AND created_at < @p3) OR ended_at IS NULL"},
+	}
+	for _, tc := range testCases {
+		sql, args := p.Build(tc.ph, tc.first)
+		if sql != tc.sql {
+			t.Fatalf("Expected %s, got %s", tc.sql, sql)
+		}
+		if !reflect.DeepEqual(args, []any{from, to}) {
+			t.Fatalf("Expected %v, got %v", []any{from, to}, args)
+		}
+	}
+	if sql := p.SQL(Dollar); sql != "(created_at >= $1 AND created_at < $2) OR ended_at IS NULL" {
+		t.Fatalf("Unexpected SQL %s", sql)
+	}
+	if sql, args := (Predicate{}).Build(Dollar, 1); sql != "1 = 1" || len(args) != 0 {
+		t.Fatalf("Expected 1 = 1, got %s, %v", sql, args)
+	}
+}
+
+func TestPredicate_ArgsCopy(t *testing.T) {
+	p := Before("created_at", to)
+	p.Args()[0] = from
+	if args := p.Args(); args[0] != to {
+		t.Fatalf("Expected %v, got %v", to, args[0])
+	}
+}
+
+func TestPlaceholderOf(t *testing.T) {
+	testCases := map[string]Placeholder{
+		"postgres":  Dollar,
+		"pgx":       Dollar,
+		"sqlserver": AtP,
+		"mssql":     AtP,
+		"mysql":     Question,
+		"sqlite":    Question,
+		"sqlite3":   Question,
+		"unknown":   Question,
+	}
+	for name, expected := range testCases {
+		if ph := PlaceholderOf(name); ph != expected {
+			t.Fatalf("%s: expected %v, got %v", name, expected, ph)
+		}
+	}
+}
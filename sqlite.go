@@ -0,0 +1,204 @@
+package timi
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"math"
+	"strconv"
+	"time"
+)
+
+// SQLite Storage Modes
+// SQLite has no datetime type; its date functions accept ISO 8601 text, Unix
+// seconds and Julian day numbers. SQLiteText, SQLiteUnix and SQLiteJulian
+// embed Time, so they keep its methods and encodings, and differ in the
+// representation Value writes and in how Scan reads a REAL. Scan of all three
+// accepts every other representation, so a column written by other tools can
+// be read by any of them.
+//
+// Scan reads:
+//   - TEXT in the formats of the SQLite date functions, such as
+//     "2006-01-02 15:04:05.000", with an optional "T", fraction and "Z" or
+//     offset, and numeric text as below
+//   - INTEGER as Unix seconds
+//   - REAL as a Julian day number into SQLiteJulian and as Unix seconds with
+//     a fraction into SQLiteUnix. SQLiteText cannot tell them apart and reads
+//     REAL below 1e7 as a Julian day number, covering years up to 22666, and
+//     other REAL values as Unix seconds, so small Unix times such as those of
+//     January 1970 are misread.
+//   - time.Time, as returned by drivers for DATETIME columns
+//
+// Infinity and NegInfinity are written as "infinity" and "-infinity" in
+// TEXT, math.MaxInt64 and math.MinInt64 in INTEGER, and +Inf and -Inf in
+// REAL, which sort after and before every finite value.
+
+// SQLiteTextLayout is the layout of SQLiteText values: the UTC time with
+// nanoseconds, padded to a fixed width so that ORDER BY on TEXT columns sorts
+// chronologically for years 0000 to 9999. The SQLite date functions accept
+// it.
+const SQLiteTextLayout = "2006-01-02 15:04:05.000000000"
+
+// julianUnixEpoch is the Julian day number of the Unix epoch.
+const julianUnixEpoch = 2440587.5
+
+// maxJulianDay is the bound below which SQLiteText reads a REAL as a Julian
+// day number rather than as Unix seconds.
+const maxJulianDay = 1e7
+
+// sqliteReal selects how Scan reads a REAL.
+type sqliteReal int
+
+const (
+	// realGuess reads a REAL below maxJulianDay as a Julian day number, and
+	// other REAL values as Unix seconds.
+	realGuess sqliteReal = iota
+	realJulian
+	realUnix
+)
+
+var sqliteLayouts = []string{
+	"2006-01-02 15:04:05.999999999Z07:00",
+	"2006-01-02T15:04:05.999999999Z07:00",
+	"2006-01-02 15:04:05.999999999",
+	"2006-01-02T15:04:05.999999999",
+	"2006-01-02 15:04Z07:00",
+	"2006-01-02T15:04Z07:00",
+	"2006-01-02 15:04",
+	"2006-01-02T15:04",
+	"2006-01-02",
+}
+
+// SQLiteText is a Time stored as sortable ISO 8601 text in SQLiteTextLayout.
+type SQLiteText struct{ Time }
+
+// SQLiteUnix is a Time stored as Unix seconds in an INTEGER. Fractions of a
+// second are truncated.
+type SQLiteUnix struct{ Time }
+
+// SQLiteJulian is a Time stored as a Julian day number in a REAL, the value
+// of julianday(). It keeps millisecond precision, like the SQLite date
+// functions.
+type SQLiteJulian struct{ Time }
+
+// Value implements the driver.Valuer interface.
+func (t SQLiteText) Value() (driver.Value, error) {
+	if !t.Valid {
+		return nil, nil
+	}
+	if inf := t.infinityText(); inf != "" {
+		return inf, nil
+	}
+	return t.Time.Time.UTC().Format(SQLiteTextLayout), nil
+}
+
+// Scan implements the sql.Scanner interface.
+func (t *SQLiteText) Scan(value interface{}) error {
+	return t.Time.scanSQLite(value, realGuess)
+}
+
+// Value implements the driver.Valuer interface.
+func (t SQLiteUnix) Value() (driver.Value, error) {
+	switch {
+	case !t.Valid:
+		return nil, nil
+	case t.IsInfinity():
+		return int64(math.MaxInt64), nil
+	case t.IsNegInfinity():
+		return int64(math.MinInt64), nil
+	}
+	return t.Time.Time.Unix(), nil
+}
+
+// Scan implements the sql.Scanner interface.
+func (t *SQLiteUnix) Scan(value interface{}) error {
+	return t.Time.scanSQLite(value, realUnix)
+}
+
+// Value implements the driver.Valuer interface.
+func (t SQLiteJulian) Value() (driver.Value, error) {
+	switch {
+	case !t.Valid:
+		return nil, nil
+	case t.IsInfinity():
+		return math.Inf(1), nil
+	case t.IsNegInfinity():
+		return math.Inf(-1), nil
+	}
+	return julianUnixEpoch + float64(t.Time.Time.UnixMilli())/(24*60*60*1000), nil
+}
+
+// Scan implements the sql.Scanner interface.
+func (t *SQLiteJulian) Scan(value interface{}) error {
+	return t.Time.scanSQLite(value, realJulian)
+}
+
+// scanSQLite scans any of the SQLite representations into t, reading a REAL
+// as real selects.
+func (t *Time) scanSQLite(value interface{}, real sqliteReal) error {
+	switch v := value.(type) {
+	case nil:
+		*t = NilTime
+	case int64:
+		*t = fromUnixSeconds(v)
+	case float64:
+		*t = fromSQLiteReal(v, real)
+	case time.Time:
+		*t = Time{Time: v.UTC(), Valid: true}
+	case []byte:
+		return t.scanSQLiteText(string(v), real)
+	case string:
+		return t.scanSQLiteText(v, real)
+	default:
+		return fmt.Errorf("timi: cannot scan %T into an SQLite time", value)
+	}
+	return nil
+}
+
+func (t *Time) scanSQLiteText(s string, real sqliteReal) error {
+	if inf, ok := parseInfinityText(s); ok {
+		*t = inf
+		return nil
+	}
+	for _, layout := range sqliteLayouts {
+		if tv, err := time.Parse(layout, s); err == nil {
+			*t = Time{Time: tv.UTC(), Valid: true}
+			return nil
+		}
+	}
+	if i, err := strconv.ParseInt(s, 10, 64); err == nil {
+		*t = fromUnixSeconds(i)
+		return nil
+	}
+	if f, err := strconv.ParseFloat(s, 64); err == nil {
+		*t = fromSQLiteReal(f, real)
+		return nil
+	}
+	return fmt.Errorf("timi: cannot parse %q as an SQLite time", s)
+}
+
+func fromUnixSeconds(sec int64) Time {
+	switch sec {
+	case math.MaxInt64:
+		return Infinity
+	case math.MinInt64:
+		return NegInfinity
+	}
+	return Time{Time: time.Unix(sec, 0).UTC(), Valid: true}
+}
+
+func fromSQLiteReal(f float64, real sqliteReal) Time {
+	switch {
+	case math.IsNaN(f):
+		// SQLite stores NaN as NULL
+		return NilTime
+	case math.IsInf(f, 1):
+		return Infinity
+	case math.IsInf(f, -1):
+		return NegInfinity
+	case real == realJulian || real == realGuess && f >= 0 && f < maxJulianDay:
+		ms := math.Round((f - julianUnixEpoch) * 24 * 60 * 60 * 1000)
+		return Time{Time: time.UnixMilli(int64(ms)).UTC(), Valid: true}
+	}
+	sec, frac := math.Modf(f)
+	return Time{Time: time.Unix(int64(sec), int64(math.Round(frac*1e9))).UTC(), Valid: true}
+}
@@ -15,6 +15,9 @@ func (t Time) Zone() (string, int) {
 }
 
 func (t Time) String() string {
+	if inf := t.infinityText(); inf != "" {
+		return inf
+	}
 	return t.Time.String()
 }
 
@@ -22,10 +25,15 @@ func (t *Time) IsNull() bool {
 	return !t.Valid
 }
 
-// IsZero reports whether t represents the zero time instant,
-// January 1, year 1, 00:00:00 UTC.
+// IsZero reports whether t is null. A valid time is never zero, including
+// the instant January 1, year 1, 00:00:00 UTC; use t.Time.IsZero to test for
+// that instant.
+//
+// The omitzero option of encoding/json, and the omitempty option of the
+// MongoDB driver and of YAML encoders, call IsZero, so they omit null times
+// and always emit valid ones.
 func (t Time) IsZero() bool {
-	return t.Time.IsZero()
+	return !t.Valid
 }
 
 // After reports whether the time instant t is after u.
@@ -127,6 +135,9 @@ func (t Time) YearDay() int {
 // so, for example, adding one month to October 31 yields
 // December 1, the normalized form for November 31.
 func (t Time) AddDate(years int, months int, days int) Time {
+	if t.IsInfinite() {
+		return t
+	}
 	t.Time = t.Time.AddDate(years, months, days)
 	return t
 }
@@ -139,6 +150,9 @@ func (t Time) AddDate(years int, months int, days int) Time {
 // time. Thus, Truncate(Hour) may return a time with a non-zero
 // minute, depending on the time's Location.
 func (t Time) Truncate(d time.Duration) Time {
+	if t.IsInfinite() {
+		return t
+	}
 	t.Time = t.Time.Truncate(d)
 	return t
 }
@@ -152,19 +166,25 @@ func (t Time) Truncate(d time.Duration) Time {
 // time. Thus, Round(Hour) may return a time with a non-zero
 // minute, depending on the time's Location.
 func (t Time) Round(d time.Duration) Time {
+	if t.IsInfinite() {
+		return t
+	}
 	t.Time = t.Time.Round(d)
 	return t
 }
 
-// Add returns the time t+d.
+// Add returns the time t+d. Infinity and NegInfinity are returned unchanged.
 func (t Time) Add(d time.Duration) Time {
+	if t.IsInfinite() {
+		return t
+	}
 	t.Time = t.Time.Add(d)
 	return t
 }
 
 // Sub returns the duration t-u. If the result exceeds the maximum (or minimum)
 // value that can be stored in a Duration, the maximum (or minimum) duration
-// will be returned.
+// will be returned, as it is whenever t or u is infinite and the other is not.
 // To compute t-d for a duration d, use t.Add(-d).
 func (t Time) Sub(u Time) time.Duration {
 	return t.Time.Sub(u.Time)
@@ -208,7 +228,22 @@ func (t Time) UnixNano() int64 {
 	return t.Time.UnixNano()
 }
 
+// Scan implements the sql.Scanner interface. Besides times and NULL, it
+// accepts the strings "infinity" and "-infinity", as returned by PostgreSQL
+// drivers, and scans them as Infinity and NegInfinity.
 func (t *Time) Scan(value interface{}) (err error) {
+	switch v := value.(type) {
+	case string:
+		if inf, ok := parseInfinityText(v); ok {
+			*t = inf
+			return nil
+		}
+	case []byte:
+		if inf, ok := parseInfinityText(v); ok {
+			*t = inf
+			return nil
+		}
+	}
 	if err = (*sql.NullTime)(t).Scan(value); err != nil {
 		return err
 	}
@@ -218,7 +253,13 @@ func (t *Time) Scan(value interface{}) (err error) {
 	return
 }
 
+// Value implements the driver.Valuer interface. Infinity and NegInfinity
+// are written as the strings "infinity" and "-infinity", which PostgreSQL
+// accepts for timestamp and timestamptz columns.
 func (t Time) Value() (driver.Value, error) {
+	if inf := t.infinityText(); inf != "" {
+		return inf, nil
+	}
 	return sql.NullTime(t).Value()
 }
 
@@ -226,7 +267,24 @@ func (t Time) MarshalJSON() ([]byte, error) {
 	if !t.Valid {
 		return []byte{'n', 'u', 'l', 'l'}, nil
 	}
-	return t.Time.MarshalJSON()
+	return t.AppendJSON(make([]byte, 0, len(`"`+time.RFC3339Nano+`"`)))
+}
+
+// AppendJSON appends the JSON encoding of t to b and returns the extended
+// buffer. It produces the same output as MarshalJSON without allocating when
+// b has enough capacity.
+func (t Time) AppendJSON(b []byte) ([]byte, error) {
+	if !t.Valid {
+		return append(b, "null"...), nil
+	}
+	if inf := t.infinityJSON(); inf != "" {
+		return append(b, inf...), nil
+	}
+	b, err := t.Time.AppendText(append(b, '"'))
+	if err != nil {
+		return b, err
+	}
+	return append(b, '"'), nil
 }
 
 func (t *Time) UnmarshalJSON(data []byte) error {
@@ -234,6 +292,16 @@ func (t *Time) UnmarshalJSON(data []byte) error {
 		t.Time, t.Valid = time.Time{}, false
 		return nil
 	}
+	if inf, ok := parseInfinityJSON(data); ok {
+		*t = inf
+		return nil
+	}
+	if len(data) > 2 && data[0] == '"' && data[len(data)-1] == '"' {
+		if tv, ok := parseRFC3339(data[1 : len(data)-1]); ok {
+			t.Time, t.Valid = tv, true
+			return nil
+		}
+	}
 	t.Valid = true
 	if err := t.Time.UnmarshalJSON(data); err != nil {
 		return err
@@ -242,15 +310,53 @@ func (t *Time) UnmarshalJSON(data []byte) error {
 	return nil
 }
 
+// MarshalText implements the encoding.TextMarshaler interface.
+// Infinity and NegInfinity are encoded as "infinity" and "-infinity".
 func (t Time) MarshalText() ([]byte, error) {
+	if inf := t.infinityText(); inf != "" {
+		return []byte(inf), nil
+	}
 	return t.Time.MarshalText()
 }
 
+// AppendText implements the encoding.TextAppender interface.
+// It appends the same representation as MarshalText to b without
+// allocating when b has enough capacity.
+func (t Time) AppendText(b []byte) ([]byte, error) {
+	if inf := t.infinityText(); inf != "" {
+		return append(b, inf...), nil
+	}
+	return t.Time.AppendText(b)
+}
+
+// MarshalYAML implements the yaml.Marshaler interface of gopkg.in/yaml.v3
+// without importing it. A null time is encoded as YAML null, and a valid time
+// as its MarshalText representation. Decoding uses UnmarshalText, and YAML
+// null decodes to NilTime.
+func (t Time) MarshalYAML() (any, error) {
+	if !t.Valid {
+		return nil, nil
+	}
+	b, err := t.MarshalText()
+	if err != nil {
+		return nil, err
+	}
+	return string(b), nil
+}
+
 func (t *Time) UnmarshalText(data []byte) error {
 	if len(data) == 4 && data[0] == 'n' && data[1] == 'u' && data[2] == 'l' && data[3] == 'l' {
 		t.Time, t.Valid = time.Time{}, false
 		return nil
 	}
+	if inf, ok := parseInfinityText(data); ok {
+		*t = inf
+		return nil
+	}
+	if tv, ok := parseRFC3339(data); ok {
+		t.Time, t.Valid = tv, true
+		return nil
+	}
 	t.Valid = true
 	if err := t.Time.UnmarshalText(data); err != nil {
 		return err
@@ -1,7 +1,12 @@
 package timi
 
 import (
+	"bytes"
+	"database/sql"
+	"database/sql/driver"
+	"encoding/gob"
 	"encoding/json"
+	"math"
 	"testing"
 	"time"
 )
@@ -82,3 +87,1061 @@ func TestTime_UnmarshalJSON(t *testing.T) {
 		}
 	}
 }
+
+func TestTime_MarshalBinary(t *testing.T) {
+	testCases := []struct {
+		name     string
+		time     Time
+		expected []byte
+	}{
+		{"Null", NilTime, []byte{0x00}},
+		{"UnixEpoch", Date(1970, 1, 1, 0, 0, 0, 0, time.UTC), []byte{0x01, 0x00, 0x00}},
+		{"Nanoseconds", Date(1970, 1, 1, 0, 0, 1, 500, time.UTC), []byte{0x01, 0x02, 0xf4, 0x03}},
+		{"BeforeEpoch", Date(1969, 12, 31, 23, 59, 59, 0, time.UTC), []byte{0x01, 0x01, 0x00}},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			data, err := tc.time.MarshalBinary()
+			if err != nil {
+				t.Fatalf("MarshalBinary failed: %v", err)
+			}
+			if !bytes.Equal(data, tc.expected) {
+				t.Fatalf("Expected bytes %x, got %x", tc.expected, data)
+			}
+			var decoded Time
+			if err = decoded.UnmarshalBinary(data); err != nil {
+				t.Fatalf("UnmarshalBinary failed: %v", err)
+			}
+			if decoded.IsNull() != tc.time.IsNull() || !decoded.Equal(tc.time) {
+				t.Fatalf("Round trip failed: expected %v, got %v", tc.time, decoded)
+			}
+		})
+	}
+
+	appended, err := Date(1970, 1, 1, 0, 0, 0, 0, time.UTC).AppendBinary([]byte("key:"))
+	if err != nil {
+		t.Fatalf("AppendBinary failed: %v", err)
+	}
+	if string(appended) != "key:\x01\x00\x00" {
+		t.Fatalf("AppendBinary did not append to the buffer: %x", appended)
+	}
+
+	invalid := [][]byte{
+		nil,
+		{0x00, 0x00},
+		{0x02, 0x00, 0x00},
+		{0x01},
+		{0x01, 0x00},
+		{0x01, 0x00, 0x80, 0x94, 0xeb, 0xdc, 0x03},
+		{0x01, 0x00, 0x00, 0x00},
+	}
+	for index, data := range invalid {
+		var decoded Time
+		if err := decoded.UnmarshalBinary(data); err == nil {
+			t.Fatalf("Case %d: Expecting error for %x, but got nil", index, data)
+		}
+	}
+}
+
+func TestTime_Gob(t *testing.T) {
+	type TimeTestStruct struct {
+		Time1 Time
+		Time2 Time
+	}
+	original := TimeTestStruct{Time1: Date(2021, 1, 1, 12, 30, 45, 123456789, time.UTC), Time2: NilTime}
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(original); err != nil {
+		t.Fatalf("Got error while encoding gob %v", err)
+	}
+	var decoded TimeTestStruct
+	if err := gob.NewDecoder(&buf).Decode(&decoded); err != nil {
+		t.Fatalf("Got error while decoding gob %v", err)
+	}
+	if !decoded.Time1.Equal(original.Time1) || decoded.Time1.IsNull() {
+		t.Fatalf("Time1 mismatch: expected %v, got %v", original.Time1, decoded.Time1)
+	}
+	if !decoded.Time2.IsNull() {
+		t.Fatalf("Time2 should be null but got %v", decoded.Time2)
+	}
+}
+
+func TestTime_AppendText(t *testing.T) {
+	ti := Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)
+	text, err := ti.AppendText([]byte("at="))
+	if err != nil {
+		t.Fatalf("AppendText failed: %v", err)
+	}
+	if string(text) != "at=2021-01-01T00:00:00Z" {
+		t.Fatalf("Expected %s, got %s", "at=2021-01-01T00:00:00Z", text)
+	}
+}
+
+func TestAppendUnixDecimal(t *testing.T) {
+	testCases := []struct {
+		time     time.Time
+		pow10    int64
+		expected string
+	}{
+		{time.Unix(0, 0), 1, "0"},
+		{time.Unix(0, 5), 1e3, "0.000005"},
+		{time.Unix(-1, 500000000), 1, "-0.5"},
+		{time.Unix(-2, 0), 1e3, "-2000"},
+		{time.Unix(1, 1), 1e9, "1000000001"},
+	}
+	for _, tc := range testCases {
+		actual := string(appendUnixDecimal(nil, tc.time, tc.pow10))
+		if actual != tc.expected {
+			t.Errorf("appendUnixDecimal(%v, %d): expected %s, got %s", tc.time, tc.pow10, tc.expected, actual)
+		}
+		parsed, err := parseUnixDecimal(actual, tc.pow10)
+		if err != nil || !parsed.Equal(tc.time) {
+			t.Errorf("parseUnixDecimal(%s, %d): expected %v, got %v (%v)", actual, tc.pow10, tc.time, parsed, err)
+		}
+	}
+}
+
+func TestParseRFC3339(t *testing.T) {
+	testCases := []string{
+		"2021-01-01T00:00:00Z",
+		"2021-01-01T00:00:00.1Z",
+		"2021-01-01T00:00:00.123456789Z",
+		"2021-06-15T12:30:45.5+05:30",
+		"2021-06-15T12:30:45-08:00",
+		"2020-02-29T23:59:59.999999999+23:59",
+		"0000-01-01T00:00:00Z",
+		"9999-12-31T23:59:59-00:00",
+	}
+	for _, s := range testCases {
+		expected, err := time.Parse(time.RFC3339Nano, s)
+		if err != nil {
+			t.Fatalf("time.Parse(%s) failed: %v", s, err)
+		}
+		actual, ok := parseRFC3339([]byte(s))
+		if !ok {
+			t.Fatalf("parseRFC3339(%s) did not take the fast path", s)
+		}
+		if !actual.Equal(expected) || actual.Location() != time.UTC {
+			t.Fatalf("parseRFC3339(%s): expected %v, got %v", s, expected.UTC(), actual)
+		}
+	}
+
+	fallbacks := []string{
+		"",
+		"2021-01-01",
+		"2021-01-01 00:00:00Z",
+		"2021-01-01T00:00:00",
+		"2021-01-01T00:00:00z",
+		"2021-02-29T00:00:00Z",
+		"2021-13-01T00:00:00Z",
+		"2021-01-01T24:00:00Z",
+		"2021-01-01T00:00:60Z",
+		"2021-01-01T00:00:00.Z",
+		"2021-01-01T00:00:00,5Z",
+		"2021-01-01T00:00:00.1234567891Z",
+		"2021-01-01T00:00:00+24:00",
+		"2021-01-01T00:00:00+0100",
+		"+2021-01-01T00:00:00Z",
+	}
+	for _, s := range fallbacks {
+		if _, ok := parseRFC3339([]byte(s)); ok {
+			t.Fatalf("parseRFC3339(%q) should fall back to the time package", s)
+		}
+	}
+}
+
+func TestTime_AppendJSON(t *testing.T) {
+	ti := Date(2021, 1, 1, 12, 30, 45, 123456789, time.UTC)
+	actual, err := ti.AppendJSON([]byte(`{"t":`))
+	if err != nil {
+		t.Fatalf("AppendJSON failed: %v", err)
+	}
+	if string(actual) != `{"t":"2021-01-01T12:30:45.123456789Z"` {
+		t.Fatalf("Unexpected AppendJSON output %s", actual)
+	}
+	actual, _ = NilTime.AppendJSON(nil)
+	if string(actual) != "null" {
+		t.Fatalf("Expected null, got %s", actual)
+	}
+	if _, err = Date(10000, 1, 1, 0, 0, 0, 0, time.UTC).AppendJSON(nil); err == nil {
+		t.Fatalf("Expecting error for year outside of [0,9999], but got nil")
+	}
+}
+
+func TestTime_ZeroAllocations(t *testing.T) {
+	ti := Date(2021, 1, 1, 12, 30, 45, 123456789, time.UTC)
+	buf := make([]byte, 0, 64)
+	testCases := map[string]func(){
+		"AppendJSON":     func() { buf, _ = ti.AppendJSON(buf[:0]) },
+		"AppendJSONNull": func() { buf, _ = NilTime.AppendJSON(buf[:0]) },
+		"AppendText":     func() { buf, _ = ti.AppendText(buf[:0]) },
+		"AppendBinary":   func() { buf, _ = ti.AppendBinary(buf[:0]) },
+		"UnmarshalJSON": func() {
+			_ = ti.UnmarshalJSON([]byte(`"2021-06-15T12:30:45.123456+05:30"`))
+		},
+		"UnmarshalText": func() { _ = ti.UnmarshalText([]byte("2021-06-15T12:30:45Z")) },
+	}
+	for name, fn := range testCases {
+		if allocs := testing.AllocsPerRun(100, fn); allocs != 0 {
+			t.Errorf("%s: expected 0 allocations, got %v", name, allocs)
+		}
+	}
+}
+
+func TestInfinity_Compare(t *testing.T) {
+	finite := []Time{
+		Date(2021, 1, 1, 0, 0, 0, 0, time.UTC),
+		Date(1, 1, 1, 0, 0, 0, 0, time.UTC),
+		Date(-292277022399, 1, 1, 0, 0, 0, 0, time.UTC),
+		Date(292277022399, 1, 1, 0, 0, 0, 0, time.UTC),
+		Now(),
+	}
+	for _, ti := range finite {
+		if !Infinity.After(ti) || !ti.Before(Infinity) || Infinity.Compare(ti) != 1 {
+			t.Fatalf("Expected Infinity after %v", ti)
+		}
+		if !NegInfinity.Before(ti) || !ti.After(NegInfinity) || NegInfinity.Compare(ti) != -1 {
+			t.Fatalf("Expected NegInfinity before %v", ti)
+		}
+		if !ti.IsFinite() || ti.IsInfinite() {
+			t.Fatalf("Expected %v to be finite", ti)
+		}
+	}
+	if !Infinity.Equal(Infinity) || Infinity.Compare(Infinity) != 0 || !NegInfinity.Before(Infinity) {
+		t.Fatalf("Expected the infinities to compare consistently")
+	}
+	if !Infinity.IsInfinity() || Infinity.IsNegInfinity() || !NegInfinity.IsNegInfinity() || NegInfinity.IsInfinity() {
+		t.Fatalf("Expected IsInfinity and IsNegInfinity to tell the infinities apart")
+	}
+	if Infinity.IsFinite() || NegInfinity.IsFinite() || NilTime.IsFinite() || NilTime.IsInfinite() {
+		t.Fatalf("Expected the infinities and NilTime not to be finite")
+	}
+	// A null Time holding the same instant is not infinite
+	if (Time{Time: Infinity.Time}).IsInfinity() {
+		t.Fatalf("Expected a null Time not to be Infinity")
+	}
+}
+
+func TestInfinity_Arithmetic(t *testing.T) {
+	for _, inf := range []Time{Infinity, NegInfinity} {
+		results := map[string]Time{
+			"Add":         inf.Add(time.Hour),
+			"AddNegative": inf.Add(-time.Hour),
+			"AddDate":     inf.AddDate(1, 2, 3),
+			"Truncate":    inf.Truncate(time.Hour),
+			"Round":       inf.Round(time.Hour),
+		}
+		for name, got := range results {
+			if got != inf {
+				t.Fatalf("%s: expected %v, got %v", name, inf, got)
+			}
+		}
+	}
+	ti := Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)
+	if d := Infinity.Sub(ti); d != time.Duration(math.MaxInt64) {
+		t.Fatalf("Expected the maximum duration, got %v", d)
+	}
+	if d := NegInfinity.Sub(ti); d != time.Duration(math.MinInt64) {
+		t.Fatalf("Expected the minimum duration, got %v", d)
+	}
+}
+
+func TestInfinity_SQL(t *testing.T) {
+	testCases := []struct {
+		time  Time
+		value string
+	}{
+		{Infinity, "infinity"},
+		{NegInfinity, "-infinity"},
+	}
+	for _, tc := range testCases {
+		value, err := tc.time.Value()
+		if err != nil || value != tc.value {
+			t.Fatalf("Expected %v, got %v, %v", tc.value, value, err)
+		}
+		for _, src := range []any{tc.value, []byte(tc.value)} {
+			var scanned Time
+			if err = scanned.Scan(src); err != nil {
+				t.Fatalf("Scan %v failed: %v", src, err)
+			}
+			if scanned != tc.time {
+				t.Fatalf("Expected %v, got %v", tc.time, scanned)
+			}
+		}
+	}
+	var scanned Time
+	if err := scanned.Scan("tomorrow"); err == nil {
+		t.Fatalf("Expected an error for an unsupported string")
+	}
+}
+
+func TestInfinity_Encoding(t *testing.T) {
+	type TimeTestStruct struct {
+		Until Time `json:"until"`
+		Since Time `json:"since"`
+	}
+	jsonVal, err := json.Marshal(TimeTestStruct{Until: Infinity, Since: NegInfinity})
+	if err != nil {
+		t.Fatalf("Got error while marshaling to JSON %v", err)
+	}
+	expected := `{"until":"infinity","since":"-infinity"}`
+	if string(jsonVal) != expected {
+		t.Fatalf("Expected %s, got %s", expected, jsonVal)
+	}
+	var unmVal TimeTestStruct
+	if err = json.Unmarshal(jsonVal, &unmVal); err != nil {
+		t.Fatalf("Got error while unmarshaling JSON %v", err)
+	}
+	if unmVal.Until != Infinity || unmVal.Since != NegInfinity {
+		t.Fatalf("Expected the infinities, got %+v", unmVal)
+	}
+
+	for _, inf := range []Time{Infinity, NegInfinity} {
+		text, err := inf.MarshalText()
+		if err != nil || string(text) != inf.String() {
+			t.Fatalf("Expected %s, got %s, %v", inf.String(), text, err)
+		}
+		var fromText Time
+		if err = fromText.UnmarshalText(text); err != nil || fromText != inf {
+			t.Fatalf("Expected %v, got %v, %v", inf, fromText, err)
+		}
+		bin, err := inf.MarshalBinary()
+		if err != nil {
+			t.Fatalf("Got error while marshaling to binary %v", err)
+		}
+		var fromBinary Time
+		if err = fromBinary.UnmarshalBinary(bin); err != nil || !fromBinary.Equal(inf) || !fromBinary.IsInfinite() {
+			t.Fatalf("Expected %v, got %v, %v", inf, fromBinary, err)
+		}
+	}
+}
+
+func TestInfinity_JSONConfig(t *testing.T) {
+	defer func(pos, neg string) { infinityJSONValue, negInfinityJSONValue = pos, neg }(infinityJSONValue, negInfinityJSONValue)
+	if err := SetInfinityJSON(`"9999-12-31T23:59:59Z"`, `"0001-01-01T00:00:00Z"`); err != nil {
+		t.Fatalf("SetInfinityJSON failed: %v", err)
+	}
+
+	jsonVal, err := json.Marshal([]Time{Infinity, NegInfinity})
+	if err != nil {
+		t.Fatalf("Got error while marshaling to JSON %v", err)
+	}
+	expected := `["9999-12-31T23:59:59Z","0001-01-01T00:00:00Z"]`
+	if string(jsonVal) != expected {
+		t.Fatalf("Expected %s, got %s", expected, jsonVal)
+	}
+	var unmVal []Time
+	if err = json.Unmarshal([]byte(expected), &unmVal); err != nil {
+		t.Fatalf("Got error while unmarshaling JSON %v", err)
+	}
+	if unmVal[0] != Infinity || unmVal[1] != NegInfinity {
+		t.Fatalf("Expected the infinities, got %v", unmVal)
+	}
+	// The default strings are still accepted
+	if err = json.Unmarshal([]byte(`["infinity","-infinity"]`), &unmVal); err != nil || unmVal[0] != Infinity || unmVal[1] != NegInfinity {
+		t.Fatalf("Expected the infinities, got %v, %v", unmVal, err)
+	}
+
+	if err = SetInfinityJSON(`1e999`, `-1e999`); err != nil {
+		t.Fatalf("SetInfinityJSON failed: %v", err)
+	}
+	if jsonVal, err = json.Marshal([]Time{Infinity, NegInfinity}); err != nil || string(jsonVal) != `[1e999,-1e999]` {
+		t.Fatalf("Expected [1e999,-1e999], got %s, %v", jsonVal, err)
+	}
+
+	// Invalid values are rejected and leave the configuration unchanged
+	for _, values := range [][2]string{{`infinity`, `"-infinity"`}, {`"infinity"`, `null`}, {`1`, `1`}} {
+		if err = SetInfinityJSON(values[0], values[1]); err == nil {
+			t.Fatalf("Expected an error for %v", values)
+		}
+	}
+	if jsonVal, err = json.Marshal(Infinity); err != nil || string(jsonVal) != `1e999` {
+		t.Fatalf("Expected 1e999, got %s, %v", jsonVal, err)
+	}
+}
+
+func TestMySQLTime_Scan(t *testing.T) {
+	zeroDates := []any{
+		"0000-00-00",
+		"0000-00-00 00:00:00",
+		[]byte("0000-00-00 00:00:00.000000"),
+		[]byte("0000-00-00T00:00:00"),
+		// As returned with parseTime
+		time.Time{},
+	}
+	for _, src := range zeroDates {
+		scanned := MySQLTime{Now()}
+		if err := scanned.Scan(src); err != nil {
+			t.Fatalf("Scan %v failed: %v", src, err)
+		}
+		if scanned.Time != NilTime {
+			t.Fatalf("Expected %v, got %v", NilTime, scanned.Time)
+		}
+	}
+
+	// Other dates are read as they are by NaiveUTC and Time
+	expected := time.Date(2024, 1, 15, 10, 30, 45, 123456000, time.UTC)
+	testCases := []any{
+		[]byte("2024-01-15 10:30:45.123456"),
+		"2024-01-15 10:30:45.123456",
+		expected,
+	}
+	for _, src := range testCases {
+		var scanned MySQLTime
+		if err := scanned.Scan(src); err != nil || !scanned.Valid || !scanned.Time.Time.Equal(expected) {
+			t.Fatalf("Expected %v, got %v, %v", expected, scanned.Time, err)
+		}
+	}
+	var scanned MySQLTime
+	if err := scanned.Scan("0000-00-00 00:00:01"); err == nil {
+		t.Fatalf("Expected an error for a partial zero date, got %v", scanned.Time)
+	}
+
+	// Time itself does not read zero dates
+	var plain Time
+	if err := plain.Scan("0000-00-00 00:00:00"); err == nil {
+		t.Fatalf("Expected an error for a zero date, got %v", plain)
+	}
+	if err := plain.Scan(time.Time{}); err != nil || !plain.Valid {
+		t.Fatalf("Expected the zero time to scan as valid, got %v, %v", plain, err)
+	}
+}
+
+func TestMySQLTime_Value(t *testing.T) {
+	testCases := []struct {
+		time  Time
+		value any
+	}{
+		{NilTime, nil},
+		{Time{Time: time.Unix(0, 0), Valid: false}, nil},
+		{Time{Time: time.Time{}, Valid: true}, time.Time{}},
+		{Date(2024, 1, 15, 0, 0, 0, 0, time.UTC), time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC)},
+	}
+	for _, tc := range testCases {
+		value, err := MySQLTime{tc.time}.Value()
+		if err != nil || value != tc.value {
+			t.Fatalf("Expected %v, got %v, %v", tc.value, value, err)
+		}
+	}
+}
+
+func TestSQLite_Value(t *testing.T) {
+	ti := Date(2024, 1, 15, 10, 30, 45, 123456789, time.UTC)
+	testCases := []struct {
+		valuer driver.Valuer
+		value  any
+	}{
+		{SQLiteText{ti}, "2024-01-15 10:30:45.123456789"},
+		{SQLiteText{Date(2024, 1, 15, 10, 30, 45, 0, time.FixedZone("", 3600))}, "2024-01-15 09:30:45.000000000"},
+		{SQLiteText{NilTime}, nil},
+		{SQLiteText{Infinity}, "infinity"},
+		{SQLiteUnix{ti}, int64(1705314645)},
+		{SQLiteUnix{NilTime}, nil},
+		{SQLiteUnix{NegInfinity}, int64(math.MinInt64)},
+		{SQLiteJulian{Date(2000, 1, 1, 12, 0, 0, 0, time.UTC)}, 2451545.0},
+		{SQLiteJulian{NilTime}, nil},
+		{SQLiteJulian{Infinity}, math.Inf(1)},
+	}
+	for _, tc := range testCases {
+		value, err := tc.valuer.Value()
+		if err != nil || value != tc.value {
+			t.Fatalf("Expected %v, got %v, %v", tc.value, value, err)
+		}
+	}
+}
+
+func TestSQLite_Scan(t *testing.T) {
+	ti := Date(2024, 1, 15, 10, 30, 45, 0, time.UTC)
+	testCases := []struct {
+		src      any
+		expected Time
+	}{
+		{nil, NilTime},
+		{"2024-01-15 10:30:45", ti},
+		{[]byte("2024-01-15T10:30:45Z"), ti},
+		{"2024-01-15 11:30:45+01:00", ti},
+		{"2024-01-15 10:30:45.123456789", Date(2024, 1, 15, 10, 30, 45, 123456789, time.UTC)},
+		{"2024-01-15 10:30", Date(2024, 1, 15, 10, 30, 0, 0, time.UTC)},
+		{"2024-01-15", Date(2024, 1, 15, 0, 0, 0, 0, time.UTC)},
+		{"1705314645", ti},
+		{int64(1705314645), ti},
+		{time.Date(2024, 1, 15, 11, 30, 45, 0, time.FixedZone("", 3600)), ti},
+		{"infinity", Infinity},
+		{int64(math.MaxInt64), Infinity},
+		{math.Inf(-1), NegInfinity},
+	}
+	for _, tc := range testCases {
+		var text SQLiteText
+		var unix SQLiteUnix
+		var julian SQLiteJulian
+		for _, s := range []sql.Scanner{&text, &unix, &julian} {
+			if err := s.Scan(tc.src); err != nil {
+				t.Fatalf("Scan %v failed: %v", tc.src, err)
+			}
+		}
+		for _, scanned := range []Time{text.Time, unix.Time, julian.Time} {
+			if scanned.Valid != tc.expected.Valid || !scanned.Equal(tc.expected) {
+				t.Fatalf("Scan %v: expected %v, got %v", tc.src, tc.expected, scanned)
+			}
+			if scanned.Valid && scanned.Time.Location() != time.UTC {
+				t.Fatalf("Scan %v: expected UTC, got %v", tc.src, scanned.Time.Location())
+			}
+		}
+	}
+
+	// REAL is read by the storage mode; SQLiteText guesses from the value
+	realCases := []struct {
+		src                any
+		text, unix, julian Time
+	}{
+		{1705314645.5, Date(2024, 1, 15, 10, 30, 45, 500000000, time.UTC), Date(2024, 1, 15, 10, 30, 45, 500000000, time.UTC), Date(4664280, 10, 25, 0, 0, 0, 0, time.UTC)},
+		{2460324.9380208333, ti, Date(1970, 1, 29, 11, 25, 24, 938020833, time.UTC), ti},
+		{"2460324.9380208333", ti, Date(1970, 1, 29, 11, 25, 24, 938020833, time.UTC), ti},
+		// A small Unix time, such as 1970-01-02 00:00:00.5
+		{86400.5, Date(-4476, 6, 16, 0, 0, 0, 0, time.UTC), Date(1970, 1, 2, 0, 0, 0, 500000000, time.UTC), Date(-4476, 6, 16, 0, 0, 0, 0, time.UTC)},
+	}
+	for _, tc := range realCases {
+		var text SQLiteText
+		var unix SQLiteUnix
+		var julian SQLiteJulian
+		for _, s := range []sql.Scanner{&text, &unix, &julian} {
+			if err := s.Scan(tc.src); err != nil {
+				t.Fatalf("Scan %v failed: %v", tc.src, err)
+			}
+		}
+		for i, expected := range []Time{tc.text, tc.unix, tc.julian} {
+			if scanned := []Time{text.Time, unix.Time, julian.Time}[i]; !scanned.Equal(expected) {
+				t.Fatalf("Scan %v: expected %v, got %v", tc.src, expected, scanned)
+			}
+		}
+	}
+
+	var text SQLiteText
+	for _, src := range []any{"tomorrow", true} {
+		if err := text.Scan(src); err == nil {
+			t.Fatalf("Expected an error for %v", src)
+		}
+	}
+}
+
+func TestSQLite_RoundTrip(t *testing.T) {
+	ti := Date(2024, 1, 15, 10, 30, 45, 123456789, time.UTC)
+	testCases := []struct {
+		valuer   driver.Valuer
+		real     sqliteReal
+		expected Time
+	}{
+		{SQLiteText{ti}, realGuess, ti},
+		{SQLiteUnix{ti}, realUnix, ti.Truncate(time.Second)},
+		{SQLiteJulian{ti}, realJulian, ti.Truncate(time.Millisecond)},
+		{SQLiteJulian{Date(1, 1, 1, 0, 0, 0, 1000000, time.UTC)}, realJulian, Date(1, 1, 1, 0, 0, 0, 1000000, time.UTC)},
+		{SQLiteJulian{Date(9999, 12, 31, 23, 59, 59, 999000000, time.UTC)}, realJulian, Date(9999, 12, 31, 23, 59, 59, 999000000, time.UTC)},
+		{SQLiteJulian{Date(30000, 1, 1, 0, 0, 0, 0, time.UTC)}, realJulian, Date(30000, 1, 1, 0, 0, 0, 0, time.UTC)},
+	}
+	for _, tc := range testCases {
+		value, err := tc.valuer.Value()
+		if err != nil {
+			t.Fatalf("Value failed: %v", err)
+		}
+		var scanned Time
+		if err = scanned.scanSQLite(value, tc.real); err != nil {
+			t.Fatalf("Scan %v failed: %v", value, err)
+		}
+		if !scanned.Equal(tc.expected) {
+			t.Fatalf("Expected %v, got %v", tc.expected, scanned)
+		}
+	}
+}
+
+func TestSQLite_TextSortable(t *testing.T) {
+	times := []Time{
+		NegInfinity,
+		Date(999, 1, 1, 0, 0, 0, 0, time.UTC),
+		Date(2024, 1, 15, 10, 30, 45, 0, time.UTC),
+		Date(2024, 1, 15, 10, 30, 45, 1, time.UTC),
+		Date(2024, 1, 15, 10, 30, 45, 500000000, time.UTC),
+		Date(2024, 1, 15, 10, 30, 46, 0, time.UTC),
+		Date(9999, 12, 31, 23, 59, 59, 999999999, time.UTC),
+		Infinity,
+	}
+	var prev string
+	for i, ti := range times {
+		value, err := SQLiteText{ti}.Value()
+		if err != nil {
+			t.Fatalf("Value failed: %v", err)
+		}
+		if i > 0 && value.(string) <= prev {
+			t.Fatalf("Expected %s to sort after %s", value, prev)
+		}
+		prev = value.(string)
+	}
+}
+
+func TestSlice_Value(t *testing.T) {
+	testCases := []struct {
+		slice Slice
+		value any
+	}{
+		{nil, nil},
+		{Slice{}, "{}"},
+		{Slice{Date(2024, 1, 15, 10, 30, 45, 123456789, time.FixedZone("", 3600)), NilTime, Infinity, NegInfinity},
+			`{"2024-01-15 09:30:45.123456789+00:00",NULL,infinity,-infinity}`},
+		{Slice{Date(-43, 3, 15, 12, 0, 0, 0, time.UTC)}, `{"0044-03-15 12:00:00+00:00 BC"}`},
+	}
+	for _, tc := range testCases {
+		value, err := tc.slice.Value()
+		if err != nil || value != tc.value {
+			t.Fatalf("Expected %v, got %v, %v", tc.value, value, err)
+		}
+	}
+}
+
+func TestSlice_Scan(t *testing.T) {
+	ti := Date(2024, 1, 15, 10, 30, 45, 123456000, time.UTC)
+	testCases := []struct {
+		src      any
+		expected []Time
+	}{
+		{nil, nil},
+		{"{}", []Time{}},
+		{`{"2024-01-15 10:30:45.123456+00",NULL,infinity,"-infinity"}`, []Time{ti, NilTime, Infinity, NegInfinity}},
+		{[]byte(`{"2024-01-15 16:00:45.123456+05:30", null }`), []Time{ti, NilTime}},
+		{`{"2024-01-15 10:30:45.123456"}`, []Time{ti}},
+		{`{2024-01-15T10:30:45.123456Z}`, []Time{ti}},
+		{`{"2024-01-15 10:30:45.123456+00:00:00"}`, []Time{ti}},
+		{`{"0044-03-15 12:00:00+00 BC","0044-01-01 00:00:00+01 BC"}`, []Time{Date(-43, 3, 15, 12, 0, 0, 0, time.UTC), Date(-43, 1, 1, 0, 0, 0, 0, time.FixedZone("", 3600))}},
+		{`[1:2]={"2024-01-15 10:30:45.123456+00",NULL}`, []Time{ti, NilTime}},
+		{`{"2024-01-15 \"10:30:45.123456+00"}`, nil},
+		{`["2024-01-15T10:30:45.123456Z",null,"infinity"]`, []Time{ti, NilTime, Infinity}},
+		{[]byte(`[]`), []Time{}},
+		{"null", nil},
+	}
+	for _, tc := range testCases {
+		for _, s := range []sql.Scanner{&Slice{Now()}, &JSONSlice{Now()}} {
+			err := s.Scan(tc.src)
+			if tc.expected == nil && tc.src != nil && tc.src != "null" {
+				if err == nil {
+					t.Fatalf("Expected an error for %v", tc.src)
+				}
+				continue
+			}
+			if err != nil {
+				t.Fatalf("Scan %v failed: %v", tc.src, err)
+			}
+			var got []Time
+			switch v := s.(type) {
+			case *Slice:
+				got = *v
+			case *JSONSlice:
+				got = *v
+			}
+			if (got == nil) != (tc.expected == nil) || len(got) != len(tc.expected) {
+				t.Fatalf("Scan %v: expected %v, got %v", tc.src, tc.expected, got)
+			}
+			for i := range got {
+				if got[i].Valid != tc.expected[i].Valid || !got[i].Equal(tc.expected[i]) {
+					t.Fatalf("Scan %v: element %d: expected %v, got %v", tc.src, i, tc.expected[i], got[i])
+				}
+			}
+		}
+	}
+	var s Slice
+	for _, src := range []any{`{{"2024-01-15 10:30:45+00"}}`, `{"NULL"}`, `{"2024-01-15`, `{tomorrow}`, `{NULL`, "2024-01-15", 42} {
+		if err := s.Scan(src); err == nil {
+			t.Fatalf("Expected an error for %v", src)
+		}
+	}
+}
+
+func TestSlice_RoundTrip(t *testing.T) {
+	slice := Slice{Date(2024, 1, 15, 10, 30, 45, 123456789, time.UTC), NilTime, Infinity, Date(1, 1, 1, 0, 0, 0, 0, time.UTC)}
+	for _, v := range []driver.Valuer{slice, JSONSlice(slice)} {
+		value, err := v.Value()
+		if err != nil {
+			t.Fatalf("Value failed: %v", err)
+		}
+		var got Slice
+		if err = got.Scan(value); err != nil {
+			t.Fatalf("Scan %v failed: %v", value, err)
+		}
+		if len(got) != len(slice) {
+			t.Fatalf("Expected %v, got %v", slice, got)
+		}
+		for i := range got {
+			if got[i] != slice[i] {
+				t.Fatalf("Element %d: expected %v, got %v", i, slice[i], got[i])
+			}
+		}
+	}
+	value, err := JSONSlice(slice).Value()
+	expected := `["2024-01-15T10:30:45.123456789Z",null,"infinity","0001-01-01T00:00:00Z"]`
+	if err != nil || value != expected {
+		t.Fatalf("Expected %v, got %v, %v", expected, value, err)
+	}
+}
+
+// kathmandu is a StorageZone with a quarter-hour offset.
+type kathmandu struct{}
+
+func (kathmandu) Location() *time.Location {
+	return time.FixedZone("+0545", 5*3600+45*60)
+}
+
+func TestNaive_Value(t *testing.T) {
+	ti := Date(2024, 1, 15, 10, 30, 45, 123456789, time.UTC)
+	testCases := []struct {
+		name     string
+		value    driver.Valuer
+		expected driver.Value
+	}{
+		{"utc", NaiveUTC{ti}, "2024-01-15 10:30:45.123456789"},
+		{"named zone", Naive[kathmandu]{ti}, "2024-01-15 16:15:45.123456789"},
+		{"whole seconds", NaiveUTC{Date(2024, 1, 15, 10, 30, 45, 0, time.UTC)}, "2024-01-15 10:30:45"},
+		{"null", NaiveUTC{NilTime}, nil},
+		{"infinity", NaiveUTC{Infinity}, "infinity"},
+		{"negative infinity", Naive[kathmandu]{NegInfinity}, "-infinity"},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			value, err := tc.value.Value()
+			if err != nil || value != tc.expected {
+				t.Fatalf("Expected %v, got %v, %v", tc.expected, value, err)
+			}
+		})
+	}
+}
+
+func TestNaive_Scan(t *testing.T) {
+	expected := Date(2024, 1, 15, 10, 30, 45, 123456000, time.UTC)
+	// Drivers attach the connection zone to the stored wall clock
+	chatham := time.FixedZone("+1345", 13*3600+45*60)
+
+	testCases := []struct {
+		name  string
+		value interface{}
+	}{
+		{"time in session zone", time.Date(2024, 1, 15, 10, 30, 45, 123456000, chatham)},
+		{"time in utc", expected.Time},
+		{"string", "2024-01-15 10:30:45.123456"},
+		{"bytes", []byte("2024-01-15 10:30:45.123456")},
+		{"iso", "2024-01-15T10:30:45.123456"},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var got NaiveUTC
+			if err := got.Scan(tc.value); err != nil {
+				t.Fatalf("Scan failed: %v", err)
+			}
+			if got.Time != expected {
+				t.Fatalf("Expected %v, got %v", expected, got.Time)
+			}
+		})
+	}
+
+	var named Naive[kathmandu]
+	if err := named.Scan("2024-01-15 16:15:45.123456"); err != nil || named.Time != expected {
+		t.Fatalf("Expected %v, got %v, %v", expected, named.Time, err)
+	}
+	if err := named.Scan(time.Date(2024, 1, 15, 16, 15, 45, 123456000, chatham)); err != nil || named.Time != expected {
+		t.Fatalf("Expected %v, got %v, %v", expected, named.Time, err)
+	}
+
+	var got NaiveUTC
+	if err := got.Scan("2024-01-15"); err != nil || got.Time != Date(2024, 1, 15, 0, 0, 0, 0, time.UTC) {
+		t.Fatalf("Expected date at midnight, got %v, %v", got.Time, err)
+	}
+	if err := got.Scan("infinity"); err != nil || got.Time != Infinity {
+		t.Fatalf("Expected %v, got %v, %v", Infinity, got.Time, err)
+	}
+	if err := got.Scan(nil); err != nil || got.Time != NilTime {
+		t.Fatalf("Expected %v, got %v, %v", NilTime, got.Time, err)
+	}
+	if err := got.Scan("tomorrow"); err == nil {
+		t.Fatalf("Expected an error for an unsupported string")
+	}
+	if err := got.Scan(int64(1)); err == nil {
+		t.Fatalf("Expected an error for an unsupported type")
+	}
+}
+
+func TestTime_IsZero(t *testing.T) {
+	testCases := []struct {
+		name     string
+		time     Time
+		expected bool
+	}{
+		{"Null", NilTime, true},
+		{"NullWithInstant", Time{Time: time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC), Valid: false}, true},
+		{"ZeroInstant", Time{Time: time.Time{}, Valid: true}, false},
+		{"Unix", Date(1970, 1, 1, 0, 0, 0, 0, time.UTC), false},
+		{"Now", Now(), false},
+	}
+	for _, tc := range testCases {
+		if got := tc.time.IsZero(); got != tc.expected {
+			t.Fatalf("%s: expected %v, got %v", tc.name, tc.expected, got)
+		}
+	}
+}
+
+func TestTime_MarshalJSONOmit(t *testing.T) {
+	type TimeTestStruct struct {
+		Zero    Time  `json:"zero,omitzero"`
+		Null    Time  `json:"null,omitzero"`
+		Kept    Time  `json:"kept"`
+		Empty   Time  `json:"empty,omitempty"`
+		Pointer *Time `json:"pointer,omitempty"`
+	}
+	jsonVal, err := json.Marshal(TimeTestStruct{
+		Zero:  Time{Time: time.Time{}, Valid: true},
+		Null:  NilTime,
+		Kept:  NilTime,
+		Empty: NilTime,
+	})
+	if err != nil {
+		t.Fatalf("Got error while marshaling to JSON %v", err)
+	}
+	// omitempty has no effect on struct types, and a valid zero instant is kept
+	expected := `{"zero":"0001-01-01T00:00:00Z","kept":null,"empty":null}`
+	if string(jsonVal) != expected {
+		t.Fatalf("Expected %s, got %s", expected, jsonVal)
+	}
+}
+
+func TestTime_MarshalYAML(t *testing.T) {
+	ti := Date(2021, 1, 1, 0, 0, 0, 123000000, time.UTC)
+	v, err := ti.MarshalYAML()
+	if err != nil {
+		t.Fatalf("Got error while marshaling to YAML %v", err)
+	}
+	if v != "2021-01-01T00:00:00.123Z" {
+		t.Fatalf("Expected %v, got %v", "2021-01-01T00:00:00.123Z", v)
+	}
+	if v, err = NilTime.MarshalYAML(); err != nil || v != nil {
+		t.Fatalf("Expected nil, got %v, %v", v, err)
+	}
+}
+
+func TestOptional_UnmarshalJSON(t *testing.T) {
+	type Patch struct {
+		EndTime Optional `json:"end_time"`
+	}
+	ti := Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)
+	testCases := []struct {
+		json             string
+		unset, null, set bool
+		expected         Time
+	}{
+		{`{}`, true, false, false, NilTime},
+		{`{"end_time":null}`, false, true, false, NilTime},
+		{`{"end_time":"2021-01-01T00:00:00Z"}`, false, false, true, ti},
+	}
+	for _, tc := range testCases {
+		var p Patch
+		if err := json.Unmarshal([]byte(tc.json), &p); err != nil {
+			t.Fatalf("%s: got error while unmarshaling JSON %v", tc.json, err)
+		}
+		if p.EndTime.IsUnset() != tc.unset || p.EndTime.IsNull() != tc.null || p.EndTime.IsSet() != tc.set {
+			t.Fatalf("%s: expected unset=%v null=%v set=%v, got %+v", tc.json, tc.unset, tc.null, tc.set, p.EndTime)
+		}
+		if tc.set && !p.EndTime.Time.Equal(tc.expected) {
+			t.Fatalf("%s: expected %v, got %v", tc.json, tc.expected, p.EndTime.Time)
+		}
+	}
+
+	var p Patch
+	if err := json.Unmarshal([]byte(`{"end_time":"yesterday"}`), &p); err == nil {
+		t.Fatalf("Expected an error for an invalid time")
+	}
+}
+
+func TestOptional_MarshalJSON(t *testing.T) {
+	type Patch struct {
+		StartTime Optional `json:"start_time,omitzero"`
+		EndTime   Optional `json:"end_time,omitzero"`
+		DeletedAt Optional `json:"deleted_at,omitzero"`
+	}
+	ti := Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)
+	jsonVal, err := json.Marshal(Patch{StartTime: OptionalOf(ti), EndTime: OptionalNull()})
+	if err != nil {
+		t.Fatalf("Got error while marshaling to JSON %v", err)
+	}
+	expected := `{"start_time":"2021-01-01T00:00:00Z","end_time":null}`
+	if string(jsonVal) != expected {
+		t.Fatalf("Expected %s, got %s", expected, jsonVal)
+	}
+
+	// Round trip keeps all three states
+	var p Patch
+	if err := json.Unmarshal(jsonVal, &p); err != nil {
+		t.Fatalf("Got error while unmarshaling JSON %v", err)
+	}
+	if !p.StartTime.IsSet() || !p.EndTime.IsNull() || !p.DeletedAt.IsUnset() {
+		t.Fatalf("Expected set, null and unset, got %+v", p)
+	}
+}
+
+func TestOptional_Apply(t *testing.T) {
+	ti := Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)
+	other := Date(2022, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	dst := other
+	Optional{}.Apply(&dst)
+	if !dst.Equal(other) {
+		t.Fatalf("Unset: expected %v, got %v", other, dst)
+	}
+	OptionalNull().Apply(&dst)
+	if !dst.IsNull() {
+		t.Fatalf("Null: expected null, got %v", dst)
+	}
+	OptionalOf(ti).Apply(&dst)
+	if !dst.Equal(ti) {
+		t.Fatalf("Set: expected %v, got %v", ti, dst)
+	}
+	if got, ok := OptionalOf(ti).Get(); !ok || !got.Equal(ti) {
+		t.Fatalf("Get: expected %v, true, got %v, %v", ti, got, ok)
+	}
+}
+
+func TestApplyPatch(t *testing.T) {
+	type Model struct {
+		Name      string
+		StartTime Time
+		EndTime   Time
+		DeletedAt Optional
+	}
+	type Patch struct {
+		Name      string // not an Optional, skipped
+		StartTime Optional
+		EndTime   Optional
+		DeletedAt Optional
+	}
+	start := Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)
+	end := Date(2021, 2, 1, 0, 0, 0, 0, time.UTC)
+	newStart := Date(2021, 1, 15, 0, 0, 0, 0, time.UTC)
+
+	model := Model{Name: "event", StartTime: start, EndTime: end}
+	patch := Patch{Name: "ignored", StartTime: OptionalOf(newStart), DeletedAt: OptionalNull()}
+	if err := ApplyPatch(&model, patch); err != nil {
+		t.Fatalf("ApplyPatch failed: %v", err)
+	}
+	if model.Name != "event" || !model.StartTime.Equal(newStart) || !model.EndTime.Equal(end) || !model.DeletedAt.IsNull() {
+		t.Fatalf("Unexpected model after patch: %+v", model)
+	}
+
+	patch = Patch{EndTime: OptionalNull()}
+	if err := ApplyPatch(&model, &patch); err != nil {
+		t.Fatalf("ApplyPatch failed: %v", err)
+	}
+	if !model.EndTime.IsNull() || !model.StartTime.Equal(newStart) {
+		t.Fatalf("Unexpected model after clearing end time: %+v", model)
+	}
+
+	if err := ApplyPatch(model, patch); err == nil {
+		t.Fatalf("Expected an error for a non-pointer destination")
+	}
+	var missing struct{ StartTime Time }
+	if err := ApplyPatch(&missing, patch); err == nil {
+		t.Fatalf("Expected an error for a missing destination field")
+	}
+	var wrongType struct{ EndTime string }
+	if err := ApplyPatch(&wrongType, patch); err == nil {
+		t.Fatalf("Expected an error for a destination field of another type")
+	}
+	if err := ApplyPatch(&model, 42); err == nil {
+		t.Fatalf("Expected an error for a non-struct patch")
+	}
+}
+
+func TestUpdateColumns(t *testing.T) {
+	type Patch struct {
+		StartTime  Optional
+		EndTime    Optional
+		ArchivedAt Optional `db:"archived_on"`
+		ReviewedAt Optional `db:"-"`
+		UserID     Optional
+		Unchanged  Optional
+		TimeField  Optional `gorm:"column:time_field_utc;not null" db:"time_field"`
+		NullTime   Optional `gorm:"type:timestamptz; Column: null_time_utc"`
+		Skipped    Optional `gorm:"-"`
+		Indexed    Optional `gorm:"index" db:"indexed_on"`
+	}
+	ti := Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)
+	columns, err := UpdateColumns(Patch{
+		StartTime:  OptionalOf(ti),
+		EndTime:    OptionalNull(),
+		ArchivedAt: OptionalOf(ti),
+		ReviewedAt: OptionalOf(ti),
+		UserID:     OptionalNull(),
+		TimeField:  OptionalOf(ti),
+		NullTime:   OptionalNull(),
+		Skipped:    OptionalOf(ti),
+		Indexed:    OptionalOf(ti),
+	})
+	if err != nil {
+		t.Fatalf("UpdateColumns failed: %v", err)
+	}
+	for _, name := range []string{"time_field_utc", "null_time_utc", "indexed_on"} {
+		if _, ok := columns[name]; !ok {
+			t.Fatalf("Expected the %s column, got %v", name, columns)
+		}
+	}
+	if len(columns) != 7 {
+		t.Fatalf("Expected 4 columns, got %v", columns)
+	}
+	if v, ok := columns["start_time"].(Time); !ok || !v.Equal(ti) {
+		t.Fatalf("Expected start_time %v, got %v", ti, columns["start_time"])
+	}
+	if v, ok := columns["end_time"].(Time); !ok || !v.IsNull() {
+		t.Fatalf("Expected end_time null, got %v", columns["end_time"])
+	}
+	if _, ok := columns["archived_on"]; !ok {
+		t.Fatalf("Expected the archived_on column from the db tag, got %v", columns)
+	}
+	if _, ok := columns["user_id"]; !ok {
+		t.Fatalf("Expected the user_id column, got %v", columns)
+	}
+	if value, err := columns["end_time"].(Time).Value(); err != nil || value != nil {
+		t.Fatalf("Expected a NULL SQL value, got %v, %v", value, err)
+	}
+}
+
+func BenchmarkTime_AppendJSON(b *testing.B) {
+	ti := Date(2021, 1, 1, 12, 30, 45, 123456789, time.UTC)
+	buf := make([]byte, 0, 64)
+	b.ReportAllocs()
+	for b.Loop() {
+		buf, _ = ti.AppendJSON(buf[:0])
+	}
+}
+
+func BenchmarkTime_AppendText(b *testing.B) {
+	ti := Date(2021, 1, 1, 12, 30, 45, 123456789, time.UTC)
+	buf := make([]byte, 0, 64)
+	b.ReportAllocs()
+	for b.Loop() {
+		buf, _ = ti.AppendText(buf[:0])
+	}
+}
+
+func BenchmarkTime_MarshalJSON(b *testing.B) {
+	ti := Date(2021, 1, 1, 12, 30, 45, 123456789, time.UTC)
+	b.ReportAllocs()
+	for b.Loop() {
+		_, _ = ti.MarshalJSON()
+	}
+}
+
+func BenchmarkTime_UnmarshalJSON(b *testing.B) {
+	inputs := map[string][]byte{
+		"UTC":    []byte(`"2021-01-01T12:30:45.123456789Z"`),
+		"Offset": []byte(`"2021-01-01T12:30:45.123+05:30"`),
+	}
+	for name, data := range inputs {
+		b.Run(name, func(b *testing.B) {
+			var ti Time
+			b.ReportAllocs()
+			for b.Loop() {
+				_ = ti.UnmarshalJSON(data)
+			}
+		})
+	}
+}
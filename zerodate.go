@@ -0,0 +1,63 @@
+package timi
+
+import "time"
+
+// MySQL Zero Dates
+// With NO_ZERO_DATE disabled, MySQL and MariaDB store "0000-00-00 00:00:00"
+// in DATETIME, TIMESTAMP and DATE columns, often as the default of NOT NULL
+// columns in legacy schemas. Without parseTime, go-sql-driver/mysql returns
+// the text, which does not scan into a Time. With parseTime, it returns the
+// zero time.Time, which Time scans as the valid instant 0001-01-01 00:00:00
+// UTC.
+
+// MySQLTime is a Time read from MySQL or MariaDB columns that may hold zero
+// dates. Scan reads a zero date as NilTime, both the text returned without
+// parseTime and the zero time.Time returned with it, and other text as a UTC
+// wall clock, as NaiveUTC does. Other values scan as they do into Time.
+// Value writes NULL for null times only, as Time does.
+type MySQLTime struct{ Time }
+
+// Scan implements the sql.Scanner interface.
+func (t *MySQLTime) Scan(value interface{}) error {
+	switch v := value.(type) {
+	case string:
+		return t.scanText(v)
+	case []byte:
+		return t.scanText(string(v))
+	case time.Time:
+		if v.IsZero() {
+			t.Time = NilTime
+			return nil
+		}
+	}
+	return t.Time.Scan(value)
+}
+
+func (t *MySQLTime) scanText(s string) error {
+	if isZeroDate(s) {
+		t.Time = NilTime
+		return nil
+	}
+	var n NaiveUTC
+	if err := n.scanText(s, time.UTC); err != nil {
+		return err
+	}
+	t.Time = n.Time
+	return nil
+}
+
+// isZeroDate reports whether s is a MySQL zero date, such as "0000-00-00",
+// "0000-00-00 00:00:00" or "0000-00-00 00:00:00.000000".
+func isZeroDate(s string) bool {
+	if len(s) < len("0000-00-00") || s[:len("0000-00-00")] != "0000-00-00" {
+		return false
+	}
+	for i := len("0000-00-00"); i < len(s); i++ {
+		switch s[i] {
+		case '0', ':', '.', ' ', 'T':
+		default:
+			return false
+		}
+	}
+	return true
+}